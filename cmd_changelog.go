@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint"
+)
+
+const changelogHelp = "Generate release notes from schema changes between two local git revisions.\n\n" +
+	"\u001B[1mUsage:\u001B[0m\n" +
+	"  terraform-setter-lint changelog \u001B[2m[flags] <old-rev> <new-rev> [path]\u001B[0m\n\n" +
+	"\u001B[1mArguments:\u001B[0m\n" +
+	"  old-rev, new-rev - Git revisions to compare, e.g. `v1.25.0` and `HEAD`.\n" +
+	"  path             - Path to root directory, current dir if not provided.\n\n" +
+	"\u001B[1mFlags:\u001B[0m\n"
+
+func runChangelog(args []string) {
+	flags := flag.NewFlagSet("changelog", flag.ExitOnError)
+	format := flags.String("format", "markdown", "Output format: `markdown` or `release-note` (.changelog/*.txt file)")
+	output := flags.String("o", "", "Output `file`, stdout is used by default")
+	flags.Usage = func() {
+		_, _ = fmt.Fprint(flags.Output(), changelogHelp)
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+	if flags.NArg() != 2 && flags.NArg() != 3 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if !knownFormat(*format) {
+		exitError(fmt.Errorf("unknown changelog format `%s`", *format), 2)
+	}
+	path := "."
+	if flags.NArg() == 3 {
+		path = flags.Arg(2)
+	}
+
+	prev, err := lint.LoadRevisionSchema(path, flags.Arg(0))
+	if err != nil {
+		exitError(err, 2)
+	}
+	next, err := lint.LoadRevisionSchema(path, flags.Arg(1))
+	if err != nil {
+		exitError(err, 2)
+	}
+
+	changes := lint.DiffSchemas(prev, next)
+	err = writeOutput(*output, func(w io.Writer) error {
+		return lint.WriteChangelog(w, changes, *format)
+	})
+	if err != nil {
+		exitError(err, 2)
+	}
+}
+
+func knownFormat(format string) bool {
+	for _, f := range lint.ChangelogFormats {
+		if string(f) == format {
+			return true
+		}
+	}
+	return false
+}
+
+// writeOutput writes to the file or to stdout if the path is empty
+func writeOutput(path string, write func(w io.Writer) error) error {
+	if path == "" {
+		return write(os.Stdout)
+	}
+	f, err := os.Create(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
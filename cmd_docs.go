@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint"
+)
+
+const docsHelp = "Generate documentation pages from resource schemas.\n\n" +
+	"\u001B[1mUsage:\u001B[0m\n" +
+	"  terraform-setter-lint docs generate \u001B[2m[flags] [path]\u001B[0m\n\n" +
+	"\u001B[1mArguments:\u001B[0m\n" +
+	"  path - Path to root directory, current dir if not provided.\n\n" +
+	"Pages are written to `docs/resources` and `docs/data-sources`, text between\n" +
+	"`<!-- manual:begin <name> -->` and `<!-- manual:end <name> -->` markers is kept.\n\n" +
+	"\u001B[1mFlags:\u001B[0m\n"
+
+func runDocs(args []string) {
+	flags := flag.NewFlagSet("docs", flag.ExitOnError)
+	templates := flags.String("templates", "", "Directory with page templates: `resource.md.tmpl`, `data-source.md.tmpl`, "+
+		"`resources/<name>.md.tmpl` or `data-sources/<name>.md.tmpl`")
+	check := flags.Bool("check", false, "Don't write pages, exit with code 1 if any page is stale")
+	flags.Usage = func() {
+		_, _ = fmt.Fprint(flags.Output(), docsHelp)
+		flags.PrintDefaults()
+	}
+	if len(args) == 0 || args[0] != "generate" {
+		flags.Usage()
+		os.Exit(2)
+	}
+	_ = flags.Parse(args[1:])
+	path := "."
+	if flags.NArg() > 0 {
+		path = flags.Arg(0)
+	}
+
+	changed, err := lint.GenerateDocs(path, *templates, *check)
+	if err != nil {
+		exitError(err, 2)
+	}
+	for _, page := range changed {
+		if *check {
+			fmt.Println("stale:", page)
+		} else {
+			fmt.Println("written:", page)
+		}
+	}
+	if *check && len(changed) != 0 {
+		exitError(fmt.Errorf("%d documentation page(s) are stale, run `terraform-setter-lint docs generate`", len(changed)), 1)
+	}
+}
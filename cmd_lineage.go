@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint"
+)
+
+const lineageHelp = "Report which response struct fields feed resource attributes and which exported fields " +
+	"are not exposed by any attribute.\n\n" +
+	"\u001B[1mUsage:\u001B[0m\n" +
+	"  terraform-setter-lint lineage \u001B[2m[flags] [path]\u001B[0m\n\n" +
+	"\u001B[1mArguments:\u001B[0m\n" +
+	"  path - Path to root directory, current dir if not provided.\n\n" +
+	"\u001B[1mFlags:\u001B[0m\n"
+
+func runLineage(args []string) {
+	flags := flag.NewFlagSet("lineage", flag.ExitOnError)
+	format := flags.String("format", "text", "Output format: `text` or `json`")
+	output := flags.String("o", "", "Output `file`, stdout is used by default")
+	flags.Usage = func() {
+		_, _ = fmt.Fprint(flags.Output(), lineageHelp)
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+	if flags.NArg() > 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	known := false
+	for _, f := range lint.LineageFormats {
+		known = known || string(f) == *format
+	}
+	if !known {
+		exitError(fmt.Errorf("unknown lineage format `%s`", *format), 2)
+	}
+	path := "."
+	if flags.NArg() == 1 {
+		path = flags.Arg(0)
+	}
+
+	report, err := lint.LoadLineage(path)
+	if err != nil {
+		exitError(err, 2)
+	}
+	err = writeOutput(*output, func(w io.Writer) error {
+		return lint.WriteLineage(w, report, *format)
+	})
+	if err != nil {
+		exitError(err, 2)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint"
+)
+
+const migrateHelp = "Rewrite legacy SDK v2 APIs of resources: CRUD functions without context, `ValidateFunc`\n" +
+	"validators and `resource.Retry` calls. Usages which can't be rewritten safely are reported.\n\n" +
+	"\u001B[1mUsage:\u001B[0m\n" +
+	"  terraform-setter-lint migrate \u001B[2m[flags] [path]\u001B[0m\n\n" +
+	"\u001B[1mArguments:\u001B[0m\n" +
+	"  path - Path to root directory, current dir if not provided.\n\n" +
+	"Unified diff of the rewrites is printed unless `-write` is set.\n\n" +
+	"\u001B[1mFlags:\u001B[0m\n"
+
+func runMigrate(args []string) {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	write := flags.Bool("write", false, "Write migrated sources instead of printing the diff")
+	errorf := flags.String("errorf", "", "Function `<import path>.<name>` replacing fmt.Errorf in returned errors, "+
+		"diag.Errorf is used by default")
+	flags.Usage = func() {
+		_, _ = fmt.Fprint(flags.Output(), migrateHelp)
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+	if flags.NArg() > 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	path := "."
+	if flags.NArg() == 1 {
+		path = flags.Arg(0)
+	}
+
+	migration, err := lint.PlanMigration(path, lint.MigrationOptions{Errorf: *errorf})
+	if err != nil {
+		exitError(err, 2)
+	}
+	for _, note := range migration.Notes {
+		_, _ = fmt.Fprintln(os.Stderr, note)
+	}
+	if *write {
+		if err := migration.Write(); err != nil {
+			exitError(err, 2)
+		}
+		for _, file := range migration.Files {
+			fmt.Println("written:", file.Name)
+		}
+		return
+	}
+	diff, err := migration.Diff()
+	if err != nil {
+		exitError(err, 2)
+	}
+	fmt.Print(diff)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint"
+)
+
+const schemaHelp = "Work with resource schemas extracted from the provider sources.\n\n" +
+	"\u001B[1mUsage:\u001B[0m\n" +
+	"  terraform-setter-lint schema export \u001B[2m[-o file] [path]\u001B[0m\n" +
+	"  terraform-setter-lint schema diff \u001B[2m<old> <new>\u001B[0m\n\n" +
+	"\u001B[1mArguments:\u001B[0m\n" +
+	"  path      - Path to root directory, current dir if not provided.\n" +
+	"  old, new  - Paths to root directories or schema JSON files created with `schema export`.\n\n" +
+	"`schema diff` exits with code 1 if there are breaking changes.\n\n" +
+	"\u001B[1mFlags:\u001B[0m\n"
+
+// exitError prints the error and exits with the given code
+func exitError(err error, code int) {
+	_, _ = fmt.Fprintln(os.Stderr, err)
+	os.Exit(code)
+}
+
+func runSchema(args []string) {
+	flags := flag.NewFlagSet("schema", flag.ExitOnError)
+	output := flags.String("o", "", "Output `file` for `schema export`, stdout is used by default")
+	flags.Usage = func() {
+		_, _ = fmt.Fprint(flags.Output(), schemaHelp)
+		flags.PrintDefaults()
+	}
+	if len(args) == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	command := args[0]
+	_ = flags.Parse(args[1:])
+
+	switch command {
+	case "export":
+		path := "."
+		if flags.NArg() > 0 {
+			path = flags.Arg(0)
+		}
+		schemas, err := lint.LoadSchemaSnapshot(path)
+		if err != nil {
+			exitError(err, 2)
+		}
+		if *output == "" {
+			err = schemas.Write(os.Stdout)
+		} else {
+			err = schemas.WriteFile(*output)
+		}
+		if err != nil {
+			exitError(err, 2)
+		}
+	case "diff":
+		if flags.NArg() != 2 {
+			flags.Usage()
+			os.Exit(2)
+		}
+		prev, err := lint.LoadSchemaSnapshot(flags.Arg(0))
+		if err != nil {
+			exitError(err, 2)
+		}
+		next, err := lint.LoadSchemaSnapshot(flags.Arg(1))
+		if err != nil {
+			exitError(err, 2)
+		}
+		if err := lint.WriteSchemaDiff(os.Stdout, lint.DiffSchemas(prev, next)); err != nil {
+			exitError(err, 1)
+		}
+	default:
+		flags.Usage()
+		os.Exit(2)
+	}
+}
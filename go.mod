@@ -3,14 +3,23 @@ module github.com/opentelekomcloud-infra/terraform-setter-lint
 go 1.17
 
 require (
-	github.com/davecgh/go-spew v1.1.0 // indirect
-	github.com/hashicorp/errwrap v1.0.0 // indirect
 	github.com/hashicorp/go-multierror v1.1.1
-	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/hashicorp/hcl/v2 v2.10.0
+	github.com/pmezard/go-difflib v1.0.0
 	github.com/stretchr/testify v1.7.0
+	github.com/zclconf/go-cty v1.8.4
+	golang.org/x/tools v0.1.5
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c
+)
+
+require (
+	github.com/agext/levenshtein v1.2.1 // indirect
+	github.com/apparentlymart/go-textseg/v13 v13.0.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/hashicorp/errwrap v1.0.0 // indirect
+	github.com/mitchellh/go-wordwrap v0.0.0-20150314170334-ad45545899c7 // indirect
 	golang.org/x/mod v0.4.2 // indirect
 	golang.org/x/sys v0.0.0-20210510120138-977fb7262007 // indirect
-	golang.org/x/tools v0.1.5
+	golang.org/x/text v0.3.5 // indirect
 	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1 // indirect
-	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
 )
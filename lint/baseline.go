@@ -0,0 +1,107 @@
+package lint
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/snapshot"
+)
+
+// revisionPrefix marks baseline set as a git revision
+const revisionPrefix = "git:"
+
+// loadBaseline loads the schema snapshot configured as a baseline
+func loadBaseline(path string, config *Config) (*snapshot.Provider, error) {
+	if rev := strings.TrimPrefix(config.Baseline, revisionPrefix); rev != config.Baseline {
+		return revisionSnapshot(path, rev)
+	}
+	file := config.Baseline
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(path, file)
+	}
+	return LoadSchemaSnapshot(file)
+}
+
+func git(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error running git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// revisionSnapshot extracts schemas from the sources of the git revision
+func revisionSnapshot(path, rev string) (*snapshot.Provider, error) {
+	top, err := git(path, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := git(path, "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, err
+	}
+	archive, err := git(strings.TrimSpace(string(top)), "archive", "--format=tar", rev)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "terraform-setter-lint-")
+	if err != nil {
+		return nil, fmt.Errorf("error creating baseline directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	if err := extractTar(bytes.NewReader(archive), dir); err != nil {
+		return nil, fmt.Errorf("error extracting revision %s: %w", rev, err)
+	}
+	return LoadSchemaSnapshot(filepath.Join(dir, strings.TrimSpace(string(prefix))))
+}
+
+// extractTar writes regular files and directories of the archive to the directory
+func extractTar(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, filepath.Clean("/"+hdr.Name))
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o750); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+				return err
+			}
+			if err := writeFile(target, tr); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func writeFile(path string, r io.Reader) error {
+	f, err := os.Create(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil { //nolint:gosec
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
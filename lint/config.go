@@ -0,0 +1,35 @@
+package lint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileName is a name of configuration file searched in the root directory
+const ConfigFileName = ".terraform-setter-lint.yaml"
+
+// LoadConfig reads configuration from the YAML file
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+	config := core.DefaultConfig()
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// FindConfig loads configuration from the root directory, default configuration is used if there is no file
+func FindConfig(root string) (*Config, error) {
+	path := filepath.Join(root, ConfigFileName)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return core.DefaultConfig(), nil
+	}
+	return LoadConfig(path)
+}
@@ -0,0 +1,25 @@
+package lint
+
+import (
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/docs"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/generators"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/snapshot"
+)
+
+// GenerateDocs renders documentation pages of resources and data sources registered in the provider,
+// `templates` is an optional directory with user templates. Paths of changed pages are returned,
+// pages are written only if `check` is not set
+func GenerateDocs(path, templates string, check bool) ([]string, error) {
+	gens, err := loadProjectGenerators(path)
+	if err != nil {
+		return nil, err
+	}
+	var registered []*generators.Generator
+	for _, gen := range gens {
+		if gen.Resource.TypeName != "" {
+			registered = append(registered, gen)
+		}
+	}
+	g := docs.Generator{Root: path, Templates: templates}
+	return g.Generate(snapshot.FromGenerators(registered), !check)
+}
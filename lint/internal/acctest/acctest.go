@@ -0,0 +1,259 @@
+// Package acctest extracts information about the resources from the acceptance tests
+package acctest
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+	"golang.org/x/tools/go/packages"
+)
+
+const testStepType = "TestStep"
+
+// checkFuncs are `resource` package functions checking attributes,
+// with indexes of resource address and attribute key arguments
+var checkFuncs = map[string][][2]int{
+	"TestCheckResourceAttr":             {{0, 1}},
+	"TestCheckResourceAttrSet":          {{0, 1}},
+	"TestCheckNoResourceAttr":           {{0, 1}},
+	"TestCheckResourceAttrPtr":          {{0, 1}},
+	"TestMatchResourceAttr":             {{0, 1}},
+	"TestCheckTypeSetElemAttr":          {{0, 1}},
+	"TestCheckTypeSetElemNestedAttrs":   {{0, 1}},
+	"TestMatchTypeSetElemNestedAttrs":   {{0, 1}},
+	"TestCheckResourceAttrPair":         {{0, 1}, {2, 3}},
+	"TestCheckTypeSetElemAttrPair":      {{0, 1}, {2, 3}},
+	"TestCheckResourceAttrWithFunction": {{0, 1}},
+}
+
+// nestedAttrsFuncs are `resource` package functions checking attributes of a set element,
+// with indexes of the argument containing map of the element attribute keys
+var nestedAttrsFuncs = map[string]int{
+	"TestCheckTypeSetElemNestedAttrs": 2,
+	"TestMatchTypeSetElemNestedAttrs": 2,
+}
+
+// testFiles returns `_test.go` files of the package
+func testFiles(pkg *packages.Package, fSet *token.FileSet) []*ast.File {
+	var files []*ast.File
+	for _, file := range pkg.Syntax {
+		if strings.HasSuffix(fSet.File(file.Package).Name(), "_test.go") {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// isTestStepType checks if the expression is `resource.TestStep` type
+func isTestStepType(expr ast.Expr) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == testStepType
+}
+
+// testSteps returns all `resource.TestStep` literals of the file, including ones with elided type
+func testSteps(file *ast.File) []*ast.CompositeLit {
+	var steps []*ast.CompositeLit
+	ast.Inspect(file, func(node ast.Node) bool {
+		lit, ok := node.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+		if isTestStepType(lit.Type) {
+			steps = append(steps, lit)
+			return true
+		}
+		arr, ok := lit.Type.(*ast.ArrayType)
+		if !ok || !isTestStepType(arr.Elt) {
+			return true
+		}
+		for _, elt := range lit.Elts {
+			if step, ok := elt.(*ast.CompositeLit); ok && step.Type == nil {
+				steps = append(steps, step)
+			}
+		}
+		return true
+	})
+	return steps
+}
+
+func stepFields(step *ast.CompositeLit) map[string]ast.Expr {
+	fields := map[string]ast.Expr{}
+	for _, elt := range step.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if key, ok := kv.Key.(*ast.Ident); ok {
+			fields[key.Name] = kv.Value
+		}
+	}
+	return fields
+}
+
+// stringList returns string literals of `[]string{...}` expression
+func stringList(expr ast.Expr) []core.Reference {
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+	refs := make([]core.Reference, 0, len(lit.Elts))
+	for _, elt := range lit.Elts {
+		bl, ok := elt.(*ast.BasicLit)
+		if !ok || bl.Kind != token.STRING {
+			continue
+		}
+		key, _ := core.UnwrapString(bl)
+		refs = append(refs, core.Reference{Key: key, Pos: bl.Pos()})
+	}
+	return refs
+}
+
+// mapKeys returns string keys of `map[string]...{...}` literal or a variable initialized with the literal
+func mapKeys(expr ast.Expr, pkg *packages.Package) []core.Reference {
+	if ident, ok := expr.(*ast.Ident); ok {
+		obj := ident.Obj
+		if obj == nil {
+			obj = packageObject(ident.Name, pkg)
+		}
+		if obj == nil {
+			return nil
+		}
+		expr = objectValue(obj)
+	}
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+	var refs []core.Reference
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		bl, ok := kv.Key.(*ast.BasicLit)
+		if !ok || bl.Kind != token.STRING {
+			continue
+		}
+		key, _ := core.UnwrapString(bl)
+		refs = append(refs, core.Reference{Key: key, Pos: bl.Pos()})
+	}
+	return refs
+}
+
+// CollectImportSteps finds acceptance test steps with `ImportState: true`, grouped by resource type name
+func CollectImportSteps(pkgs []*packages.Package, fSet *token.FileSet) map[string][]core.ImportStep {
+	result := map[string][]core.ImportStep{}
+	for _, pkg := range pkgs {
+		for _, file := range testFiles(pkg, fSet) {
+			for _, step := range testSteps(file) {
+				fields := stepFields(step)
+				if ident, ok := fields["ImportState"].(*ast.Ident); !ok || ident.Name != "true" {
+					continue
+				}
+				address := resolveString(fields["ResourceName"], pkg)
+				if address == "" {
+					continue
+				}
+				typeName, _ := core.SplitAddress(address)
+				result[typeName] = append(result[typeName], core.ImportStep{
+					Pos:    step.Pos(),
+					Ignore: stringList(fields["ImportStateVerifyIgnore"]),
+				})
+			}
+		}
+	}
+	return result
+}
+
+// resolveString returns value of string literal, constant or variable initialized with a literal
+func resolveString(expr ast.Expr, pkg *packages.Package) string {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return ""
+		}
+		value, _ := core.UnwrapString(e)
+		return value
+	case *ast.Ident:
+		obj := e.Obj
+		if obj == nil { // declared in another file of the package
+			obj = packageObject(e.Name, pkg)
+		}
+		if obj == nil {
+			return ""
+		}
+		return resolveString(objectValue(obj), pkg)
+	}
+	return ""
+}
+
+func packageObject(name string, pkg *packages.Package) *ast.Object {
+	for _, file := range pkg.Syntax {
+		if obj, ok := file.Scope.Objects[name]; ok {
+			return obj
+		}
+	}
+	return nil
+}
+
+// objectValue returns expression assigned to the object on declaration
+func objectValue(obj *ast.Object) ast.Expr {
+	switch decl := obj.Decl.(type) {
+	case *ast.ValueSpec:
+		for i, name := range decl.Names {
+			if name.Name == obj.Name && i < len(decl.Values) {
+				return decl.Values[i]
+			}
+		}
+	case *ast.AssignStmt:
+		for i, lhs := range decl.Lhs {
+			if ident, ok := lhs.(*ast.Ident); ok && ident.Name == obj.Name && i < len(decl.Rhs) {
+				return decl.Rhs[i]
+			}
+		}
+	}
+	return nil
+}
+
+// CollectAttrChecks finds attribute keys used in acceptance test checks, grouped by resource
+func CollectAttrChecks(pkgs []*packages.Package, fSet *token.FileSet) map[core.ResourceInfo][]core.Reference {
+	result := map[core.ResourceInfo][]core.Reference{}
+	for _, pkg := range pkgs {
+		for _, file := range testFiles(pkg, fSet) {
+			ast.Inspect(file, func(node ast.Node) bool {
+				call, ok := node.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				sel, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+				for _, args := range checkFuncs[sel.Sel.Name] {
+					if len(call.Args) <= args[1] {
+						continue
+					}
+					address := resolveString(call.Args[args[0]], pkg)
+					key, ok := call.Args[args[1]].(*ast.BasicLit)
+					if address == "" || !ok || key.Kind != token.STRING {
+						continue
+					}
+					typeName, dataSource := core.SplitAddress(address)
+					info := core.ResourceInfo{TypeName: typeName, DataSource: dataSource}
+					value, _ := core.UnwrapString(key)
+					result[info] = append(result[info], core.Reference{Key: value, Pos: key.Pos()})
+					if idx, ok := nestedAttrsFuncs[sel.Sel.Name]; ok && idx < len(call.Args) {
+						for _, ref := range mapKeys(call.Args[idx], pkg) {
+							ref.Key = value + "." + ref.Key
+							result[info] = append(result[info], ref)
+						}
+					}
+				}
+				return true
+			})
+		}
+	}
+	return result
+}
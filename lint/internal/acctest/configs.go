@@ -0,0 +1,188 @@
+package acctest
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+	"golang.org/x/tools/go/packages"
+)
+
+var (
+	// configRe detects strings containing Terraform configuration
+	configRe = regexp.MustCompile(`(?m)^\s*(resource|data)\s+"`)
+	// verbRe matches `fmt.Sprintf` verbs
+	verbRe = regexp.MustCompile(`%(\[\d+])?[-+# 0]*(\d+|\*)?(\.(\d+|\*))?[a-zA-Z]`)
+)
+
+// segment is a string literal starting at the offset of the folded string
+type segment struct {
+	offset int
+	lit    *ast.BasicLit
+}
+
+// foldedString is a value of the constant string expression
+type foldedString struct {
+	text     strings.Builder
+	segments []segment
+}
+
+// fold appends value of the constant string expression, e.g. concatenation of literals and constants
+func (f *foldedString) fold(expr ast.Expr, pkg *packages.Package) bool {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return false
+		}
+		value, err := strconv.Unquote(e.Value)
+		if err != nil {
+			return false
+		}
+		f.segments = append(f.segments, segment{offset: f.text.Len(), lit: e})
+		f.text.WriteString(value)
+		return true
+	case *ast.BinaryExpr:
+		return e.Op == token.ADD && f.fold(e.X, pkg) && f.fold(e.Y, pkg)
+	case *ast.ParenExpr:
+		return f.fold(e.X, pkg)
+	case *ast.Ident:
+		obj := e.Obj
+		if obj == nil { // declared in another file of the package
+			obj = packageObject(e.Name, pkg)
+		}
+		if obj == nil || (obj.Kind != ast.Con && obj.Kind != ast.Var) {
+			return false
+		}
+		value := objectValue(obj)
+		return value != nil && f.fold(value, pkg)
+	}
+	return false
+}
+
+// pos maps offset of the folded string to the position in the source, offsets inside interpreted literals
+// with escape sequences are mapped to the literal start
+func (f *foldedString) pos(offset int) token.Pos {
+	seg := f.segments[0]
+	for _, s := range f.segments {
+		if s.offset <= offset {
+			seg = s
+		}
+	}
+	if strings.HasPrefix(seg.lit.Value, "`") || !strings.Contains(seg.lit.Value, `\`) {
+		return seg.lit.Pos() + 1 + token.Pos(offset-seg.offset)
+	}
+	return seg.lit.Pos()
+}
+
+// replaceVerbs replaces `fmt.Sprintf` verbs keeping offsets: verbs taking the whole line are blanked,
+// other ones become identifiers, which are valid both in strings and as expressions
+func replaceVerbs(text string) (string, []int, []int) {
+	result := []byte(text)
+	var interpolated, placeholders []int
+	for _, match := range verbRe.FindAllStringIndex(text, -1) {
+		start, end := match[0], match[1]
+		if start > 0 && text[start-1] == '%' {
+			continue // escaped `%%`
+		}
+		lineStart := strings.LastIndexByte(text[:start], '\n') + 1
+		lineEnd := strings.IndexByte(text[end:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(text) - end
+		}
+		if strings.TrimSpace(text[lineStart:start]) == "" && strings.TrimSpace(text[end:end+lineEnd]) == "" {
+			interpolated = append(interpolated, start)
+			for i := start; i < end; i++ {
+				result[i] = ' '
+			}
+			continue
+		}
+		placeholders = append(placeholders, start)
+		result[start] = 'v'
+		for i := start + 1; i < end; i++ {
+			result[i] = '_'
+		}
+	}
+	return string(result), interpolated, placeholders
+}
+
+// isSprintf checks if the call is `fmt.Sprintf`
+func isSprintf(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Sprintf" {
+		return false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	return ok && x.Name == "fmt"
+}
+
+// configCandidates returns string expressions of the file which can contain configurations:
+// `fmt.Sprintf` formats, returned values and constant declarations
+func configCandidates(file *ast.File) []ast.Expr {
+	var exprs []ast.Expr
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.CallExpr:
+			if isSprintf(n) && len(n.Args) != 0 {
+				exprs = append(exprs, n.Args[0])
+			}
+		case *ast.ReturnStmt:
+			for _, res := range n.Results {
+				switch res.(type) {
+				case *ast.BasicLit, *ast.BinaryExpr:
+					exprs = append(exprs, res)
+				}
+			}
+		case *ast.ValueSpec:
+			exprs = append(exprs, n.Values...)
+		}
+		return true
+	})
+	return exprs
+}
+
+// CollectConfigs finds Terraform configurations in the acceptance tests, `fmt.Sprintf` verbs are replaced
+// with placeholders of the same length, so positions in the configuration map to the Go string literals
+func CollectConfigs(pkgs []*packages.Package, fSet *token.FileSet) []core.HCLConfig {
+	var folded []*foldedString
+	seen := map[token.Pos]bool{}
+	embedded := map[token.Pos]bool{}
+	for _, pkg := range pkgs {
+		for _, file := range testFiles(pkg, fSet) {
+			for _, expr := range configCandidates(file) {
+				str := &foldedString{}
+				if !str.fold(expr, pkg) || !configRe.MatchString(str.text.String()) {
+					continue
+				}
+				start := str.segments[0].lit.Pos()
+				if seen[start] {
+					continue // constant used as a format
+				}
+				seen[start] = true
+				for _, seg := range str.segments[1:] {
+					embedded[seg.lit.Pos()] = true
+				}
+				folded = append(folded, str)
+			}
+		}
+	}
+
+	var result []core.HCLConfig
+	for _, str := range folded {
+		start := str.segments[0].lit.Pos()
+		if embedded[start] {
+			continue // validated as a part of the including configuration
+		}
+		content, interpolated, placeholders := replaceVerbs(str.text.String())
+		result = append(result, core.HCLConfig{
+			Filename:     fSet.Position(start).String(),
+			Content:      []byte(content),
+			Pos:          str.pos,
+			Interpolated: interpolated,
+			Placeholders: placeholders,
+		})
+	}
+	return result
+}
@@ -0,0 +1,156 @@
+// Package changelog renders release notes from schema changes
+package changelog
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/snapshot"
+)
+
+// Format is the release notes output format
+type Format string
+
+const (
+	// Markdown is a single document with notes grouped by sections
+	Markdown Format = "markdown"
+	// ReleaseNote is a `.changelog/*.txt` file with `release-note:<type>` blocks
+	ReleaseNote Format = "release-note"
+)
+
+// Formats are all supported output formats
+var Formats = []Format{Markdown, ReleaseNote}
+
+// Section groups notes of the same kind
+type Section struct {
+	Title string
+	// NoteType is the release note type used in `.changelog` files
+	NoteType string
+	Notes    []string
+}
+
+const dataPrefix = "data."
+
+// resourceName returns resource type name as used in release notes, e.g. `data-source/opentelekomcloud_vpc_v1`
+func resourceName(res string) string {
+	if name := strings.TrimPrefix(res, dataPrefix); name != res {
+		return "data-source/" + name
+	}
+	return "resource/" + res
+}
+
+// Sections groups changes into release note sections, empty sections are omitted
+func Sections(changes []snapshot.Change) []Section {
+	sections := []Section{
+		{Title: "New resources", NoteType: "new-resource"},
+		{Title: "New data sources", NoteType: "new-data-source"},
+		{Title: "New attributes", NoteType: "enhancement"},
+		{Title: "Deprecated attributes", NoteType: "note"},
+		{Title: "Removed attributes", NoteType: "breaking-change"},
+		{Title: "Breaking changes", NoteType: "breaking-change"},
+	}
+	const (
+		newResources = iota
+		newDataSources
+		newAttributes
+		deprecated
+		removed
+		breaking
+	)
+	for _, c := range changes {
+		switch {
+		case c.Path == "" && c.Action == snapshot.Added && strings.HasPrefix(c.Resource, dataPrefix):
+			sections[newDataSources].Notes = append(sections[newDataSources].Notes, strings.TrimPrefix(c.Resource, dataPrefix))
+		case c.Path == "" && c.Action == snapshot.Added:
+			sections[newResources].Notes = append(sections[newResources].Notes, c.Resource)
+		case c.Path != "" && c.Action == snapshot.Added && c.Kind != snapshot.Breaking:
+			sections[newAttributes].Notes = append(sections[newAttributes].Notes,
+				fmt.Sprintf("%s: Add `%s` attribute", resourceName(c.Resource), c.Path))
+		case c.Action == snapshot.Deprecated:
+			sections[deprecated].Notes = append(sections[deprecated].Notes,
+				fmt.Sprintf("%s: Attribute `%s` is %s", resourceName(c.Resource), c.Path, c.Message))
+		case c.Path != "" && c.Action == snapshot.Removed:
+			sections[removed].Notes = append(sections[removed].Notes,
+				fmt.Sprintf("%s: Remove `%s` attribute", resourceName(c.Resource), c.Path))
+		case c.Kind == snapshot.Breaking && c.Path == "":
+			sections[breaking].Notes = append(sections[breaking].Notes,
+				fmt.Sprintf("%s: Resource is %s", resourceName(c.Resource), c.Message))
+		case c.Kind == snapshot.Breaking:
+			sections[breaking].Notes = append(sections[breaking].Notes,
+				fmt.Sprintf("%s: Attribute `%s` %s", resourceName(c.Resource), c.Path, c.Message))
+		}
+	}
+
+	var result []Section
+	for _, s := range sections {
+		if len(s.Notes) != 0 {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// Write renders release notes of the changes in the given format
+func Write(w io.Writer, changes []snapshot.Change, format Format) error {
+	switch format {
+	case Markdown:
+		return writeMarkdown(w, Sections(changes))
+	case ReleaseNote:
+		return writeReleaseNotes(w, Sections(changes))
+	}
+	return fmt.Errorf("unknown changelog format `%s`", format)
+}
+
+func writeMarkdown(w io.Writer, sections []Section) error {
+	if len(sections) == 0 {
+		_, err := fmt.Fprintln(w, "No schema changes found")
+		return err
+	}
+	for i, s := range sections {
+		if i != 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "### %s\n\n", s.Title); err != nil {
+			return err
+		}
+		for _, note := range s.Notes {
+			if _, err := fmt.Fprintf(w, "- %s\n", markdownNote(s, note)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// markdownNote highlights resource names
+func markdownNote(s Section, note string) string {
+	if s.NoteType == "new-resource" || s.NoteType == "new-data-source" {
+		return "`" + note + "`"
+	}
+	parts := strings.SplitN(note, ": ", 2)
+	if len(parts) != 2 {
+		return note
+	}
+	return fmt.Sprintf("**%s**: %s", parts[0], parts[1])
+}
+
+func writeReleaseNotes(w io.Writer, sections []Section) error {
+	first := true
+	for _, s := range sections {
+		for _, note := range s.Notes {
+			if !first {
+				if _, err := fmt.Fprintln(w); err != nil {
+					return err
+				}
+			}
+			first = false
+			if _, err := fmt.Fprintf(w, "```release-note:%s\n%s\n```\n", s.NoteType, note); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package core
 import (
 	"fmt"
 	"go/ast"
+	"go/token"
 	"strings"
 
 	"golang.org/x/tools/go/packages"
@@ -19,6 +20,30 @@ type Scope struct {
 	FuncTypes   map[string]*FuncType
 	StructDecls map[string]*ast.GenDecl
 	StructTypes map[string]*StructType
+	// Helpers are summaries of `flatten*` and `expand*` functions, populated lazily
+	Helpers map[string]*HelperSummary
+}
+
+// HelperKey is a map key produced or read by a `flatten*` or `expand*` helper
+type HelperKey struct {
+	Key string
+	Pos token.Pos
+	// Type is the produced value type, nil if it can't be resolved
+	Type Type
+	// Asserted is the type the read value is asserted to, empty if the value is not asserted
+	Asserted string
+}
+
+// HelperSummary describes nested block maps built by `flatten*` and read by `expand*` helpers
+type HelperSummary struct {
+	// Name is the helper name with the package name, e.g. `common.FlattenTags`
+	Name string
+	// Produced are keys of the returned `map[string]interface{}` values
+	Produced []HelperKey
+	// Open is set if not all produced keys are known, e.g. the map is filled in a loop
+	Open bool
+	// Read are keys read from the maps of the first argument, e.g. `m["key"].(string)`
+	Read []HelperKey
 }
 
 func MethodName(receiver, fnc string) string {
@@ -27,3 +52,44 @@ func MethodName(receiver, fnc string) string {
 	}
 	return fmt.Sprintf("%s.%s", receiver, fnc)
 }
+
+func getPkgName(pkg *packages.Package) string {
+	if pkg == nil {
+		return ""
+	}
+	for _, file := range pkg.Syntax {
+		return file.Name.Name
+	}
+	return ""
+}
+
+// AbsoluteImport resolves package name used in the file containing the expression to the import path
+func AbsoluteImport(fSet *token.FileSet, pkgName string, expr ast.Expr, pkg *packages.Package) string {
+	if pkgName == pkg.ID {
+		return pkg.ID
+	}
+	srcFile := fSet.File(expr.Pos())
+	for _, fl := range pkg.Syntax {
+		flPos := fSet.File(fl.Package)
+		if flPos.Name() != srcFile.Name() { // we found file with the expression
+			continue
+		}
+		for _, i := range fl.Imports {
+			var alias string
+			path := strings.Trim(i.Path.Value, `"`)
+			if i.Name != nil {
+				alias = i.Name.Name
+			} else {
+				importedPackageName := getPkgName(pkg.Imports[path])
+				alias = importedPackageName
+			}
+			if pkgName == alias {
+				return path
+			}
+		}
+	}
+	if fallback, ok := pkg.Imports[pkgName]; ok {
+		return fallback.ID // use package import as a fallback
+	}
+	return ""
+}
@@ -0,0 +1,131 @@
+package core
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Reference is a schema key used as a string literal, e.g. in field relations or tests
+type Reference struct {
+	Key string
+	Pos token.Pos
+}
+
+// ResourceInfo describes resource registration in the provider
+type ResourceInfo struct {
+	TypeName   string
+	DataSource bool
+}
+
+// String returns the resource type name as used in the configuration, e.g. `data.opentelekomcloud_x`
+func (r ResourceInfo) String() string {
+	if r.DataSource {
+		return "data." + r.TypeName
+	}
+	return r.TypeName
+}
+
+// ImportStep is an acceptance test step verifying resource import
+type ImportStep struct {
+	Pos    token.Pos
+	Ignore []Reference // `ImportStateVerifyIgnore` values
+}
+
+// DocAttribute is an attribute listed in the resource documentation
+type DocAttribute struct {
+	Key string
+	Pos token.Pos
+	// Block is the nested block the attribute is listed for, empty for top-level attributes
+	Block string
+	// Argument is set for attributes listed in the "Argument Reference" section
+	Argument bool
+	Required bool
+	Optional bool
+}
+
+// Doc is the documentation page of a resource or a data source
+type Doc struct {
+	// Path is the file path relative to the provider root, e.g. `docs/resources/vpc_v1.md`
+	Path       string
+	Attributes []DocAttribute
+}
+
+// HCLConfig is a Terraform configuration found in the provider sources, e.g. an example or an acceptance test config
+type HCLConfig struct {
+	// Filename is the name used in HCL diagnostics
+	Filename string
+	Content  []byte
+	// Pos maps byte offset of the content to the position in the source file
+	Pos func(offset int) token.Pos
+	// Interpolated are offsets of the `fmt.Sprintf` verbs inserting whole lines, e.g. other configs or arguments
+	Interpolated []int
+	// Placeholders are offsets of other `fmt.Sprintf` verbs, replaced by identifiers of the same length
+	Placeholders []int
+}
+
+// FrameworkAttribute is an attribute or a block of the terraform-plugin-framework schema
+type FrameworkAttribute struct {
+	Name string
+	// Kind is the schema type name, e.g. `StringAttribute` or `ListNestedBlock`
+	Kind     string
+	Pos      token.Pos
+	Required bool
+	Optional bool
+	Computed bool
+	// Nested are attributes and blocks of the nested object, nil for attributes without nested objects
+	Nested map[string]*FrameworkAttribute
+}
+
+// FrameworkResource is a resource or a data source implemented with terraform-plugin-framework
+type FrameworkResource struct {
+	// Type is the name of the type implementing `resource.Resource` or `datasource.DataSource`
+	Type       string
+	DataSource bool
+	Pkg        *packages.Package
+	// Fset is the file set of the loaded packages
+	Fset   *token.FileSet
+	Pos    token.Pos
+	Schema map[string]*FrameworkAttribute
+	// Methods are all methods of the type, e.g. `Schema`, `Create` or `Read`
+	Methods []*ast.FuncDecl
+}
+
+// Project contains provider-wide information shared by all generators
+type Project struct {
+	// Resources maps generator functions (`<package>.<function>`) to their registration in the provider
+	Resources map[string]ResourceInfo
+	// ImportSteps are acceptance test steps verifying import, by resource type name
+	ImportSteps map[string][]ImportStep
+	// AttrChecks are attribute keys used in acceptance test checks, e.g. `resource.TestCheckResourceAttr`
+	AttrChecks map[ResourceInfo][]Reference
+	// Docs are documentation pages of resources and data sources
+	Docs map[ResourceInfo]*Doc
+	// Configs are example and acceptance test configurations
+	Configs []HCLConfig
+	// Framework are resources and data sources implemented with terraform-plugin-framework
+	Framework []*FrameworkResource
+	// Packages are all loaded packages, test variants included
+	Packages []*packages.Package
+}
+
+func NewProject() *Project {
+	return &Project{
+		Resources:   map[string]ResourceInfo{},
+		ImportSteps: map[string][]ImportStep{},
+		AttrChecks:  map[ResourceInfo][]Reference{},
+		Docs:        map[ResourceInfo]*Doc{},
+	}
+}
+
+// SplitAddress splits resource address, e.g. `data.opentelekomcloud_x.name`, into type name and data source flag
+func SplitAddress(address string) (string, bool) {
+	dataSource := false
+	if strings.HasPrefix(address, "data.") {
+		dataSource = true
+		address = strings.TrimPrefix(address, "data.")
+	}
+	return strings.SplitN(address, ".", 2)[0], dataSource
+}
@@ -0,0 +1,327 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/set"
+)
+
+// Rule IDs, used to select validations
+const (
+	RuleSetters                = "setters"
+	RuleSchemaReferences       = "schema-references"
+	RuleSchemaMode             = "schema-mode"
+	RuleSchemaRequiredComputed = "schema-required-computed"
+	RuleSchemaDefault          = "schema-default"
+	RuleSchemaElem             = "schema-elem"
+	RuleSchemaMaxItems         = "schema-max-items"
+	RuleSchemaComputedForceNew = "schema-computed-force-new"
+	RuleResourceUpdate         = "resource-update"
+	RuleSchemaValueTypes       = "schema-value-types"
+	RuleReadCompleteness       = "read-completeness"
+	RuleImportCompleteness     = "import-completeness"
+	RuleTestAttributes         = "test-attributes"
+	RuleUpdateCoverage         = "update-coverage"
+	RuleSensitiveLeak          = "sensitive-leak"
+	RuleAttributeConsistency   = "attribute-consistency"
+	RuleDataSourceParity       = "data-source-parity"
+	RuleStateUpgrade           = "state-upgrade"
+	RuleDocsDrift              = "docs-drift"
+	RuleExampleConfigs         = "example-configs"
+	RuleRequestTypes           = "request-types"
+	RuleHelperKeys             = "helper-keys"
+	RuleFrameworkModels        = "framework-models"
+	RuleFrameworkPaths         = "framework-paths"
+	RuleDeletedResources       = "deleted-resources"
+	RuleImplicitGetters        = "implicit-getters"
+)
+
+// AllRules is a special value selecting every known rule
+const AllRules = "all"
+
+// Rule describes single validation
+type Rule struct {
+	ID          string
+	Description string
+	Default     bool // if the rule is enabled without explicit configuration
+}
+
+// Rules lists all known rules
+var Rules = []Rule{
+	{RuleSetters, "`d.Set` calls use existing schema fields and values of a matching type", true},
+	{RuleSchemaReferences, "schema relations (`ConflictsWith`, `ExactlyOneOf`, ...) reference existing fields", true},
+	{RuleSchemaMode, "field is `Optional`, `Required` or `Computed`, but not `Required` and `Optional`", false},
+	{RuleSchemaRequiredComputed, "field is not `Required` and `Computed` at the same time", false},
+	{RuleSchemaDefault, "`Default` is not set for `Required` or `Computed` fields", false},
+	{RuleSchemaElem, "`TypeList` and `TypeSet` fields have `Elem` set", false},
+	{RuleSchemaMaxItems, "`MaxItems` and `MinItems` are used only for lists and sets", false},
+	{RuleSchemaComputedForceNew, "`ForceNew` is not set for computed-only fields", false},
+	{RuleResourceUpdate, "resource has `Update` only if some fields can be updated in place", false},
+	{RuleSchemaValueTypes, "`Default` values and `ValidateFunc` validators match the field type", false},
+	{RuleReadCompleteness, "all computed fields are set in `Read`", false},
+	{RuleImportCompleteness, "configurable fields of importable resources are set in `Read` or the importer", false},
+	{RuleTestAttributes, "attribute keys used in acceptance test checks exist in the schema", false},
+	{RuleUpdateCoverage, "`Update` handles all updatable fields and checks no `ForceNew` fields", false},
+	{RuleSensitiveLeak, "values of `Sensitive` fields don't reach logs and error messages", false},
+	{RuleAttributeConsistency, "attributes with the same name are declared the same way in all resources", false},
+	{RuleDataSourceParity, "data sources have all attributes of the paired resources with the same types", false},
+	{RuleStateUpgrade, "state-incompatible schema changes bump `SchemaVersion` and add `StateUpgraders`", false},
+	{RuleDocsDrift, "docs list all attributes, `Required` and `Optional` labels match the schema", false},
+	{RuleExampleConfigs, "example and acceptance test configurations match the schema", false},
+	{RuleRequestTypes, "`d.Get` values assigned to SDK struct fields match schema and field types", false},
+	{RuleHelperKeys, "`flatten*` and `expand*` helper map keys and types match nested schemas", false},
+	{RuleFrameworkModels, "framework model `tfsdk` tags and `types` field types match schema attributes", false},
+	{RuleFrameworkPaths, "framework `SetAttribute` and `GetAttribute` paths exist in the schema", false},
+	{RuleDeletedResources, "`Read` clears ID of deleted resources, `Delete` tolerates `ErrDefault404`", false},
+	{RuleImplicitGetters, "keys read by functions like `GetRegion(d)` are declared in the schema", false},
+}
+
+func findRule(id string) (Rule, bool) {
+	for _, r := range Rules {
+		if r.ID == id {
+			return r, true
+		}
+	}
+	return Rule{}, false
+}
+
+// ValueTypes are types which can be used for values in the configuration
+var ValueTypes = set.StringSetFromSlice([]string{"string", "int", "float", "bool", "array", "map"})
+
+// SchemaTypes are field types of the SDK schema
+var SchemaTypes = set.StringSetFromSlice([]string{
+	"TypeBool", "TypeInt", "TypeFloat", "TypeString", "TypeList", "TypeMap", "TypeSet",
+})
+
+// CanonicalField is the expected declaration of the attribute, values not set are not checked
+type CanonicalField struct {
+	// Helper is the function (`<import path>.<function>`) which must be used to declare the attribute
+	Helper   string `yaml:"helper"`
+	Type     string `yaml:"type"`
+	Required *bool  `yaml:"required"`
+	Optional *bool  `yaml:"optional"`
+	Computed *bool  `yaml:"computed"`
+	ForceNew *bool  `yaml:"force_new"`
+}
+
+// ParityRule pairs resources with data sources by type names,
+// `DataSource` is a regexp replacement template and can use groups matched by `Resource`
+type ParityRule struct {
+	Resource   string `yaml:"resource"`
+	DataSource string `yaml:"data_source"`
+}
+
+// DefaultParity pairs resources with data sources of the same type name
+var DefaultParity = []ParityRule{{Resource: "^.+$", DataSource: "$0"}}
+
+// PairedDataSource returns name of the data source paired with the resource by the first matching rule
+func (c *Config) PairedDataSource(resource string) string {
+	rules := c.Parity
+	if len(rules) == 0 {
+		rules = DefaultParity
+	}
+	for _, rule := range rules {
+		re, err := regexp.Compile(rule.Resource)
+		if err != nil {
+			continue // reported by Validate
+		}
+		if match := re.FindStringSubmatchIndex(resource); match != nil {
+			return string(re.ExpandString(nil, rule.DataSource, resource, match))
+		}
+	}
+	return ""
+}
+
+// ImplicitGetter describes keys of `*schema.ResourceData` read by the function
+type ImplicitGetter struct {
+	// Keys are keys read by the function with their expected declarations
+	Keys map[string]CanonicalField `yaml:"keys"`
+	// KeyArgument is index of the argument with the key, e.g. `1` for `common.HasFilledOpt(d, "name")`
+	KeyArgument *int `yaml:"key_argument"`
+}
+
+func boolPtr(v bool) *bool {
+	return &v
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+// DefaultImplicitGetters are functions of the provider reading `*schema.ResourceData`,
+// methods are named `<import path>.<type>.<method>`
+var DefaultImplicitGetters = map[string]ImplicitGetter{
+	"github.com/opentelekomcloud/terraform-provider-opentelekomcloud/opentelekomcloud/common/cfg.Config.GetRegion": {
+		Keys: map[string]CanonicalField{
+			"region": {Type: "TypeString", Optional: boolPtr(true), Computed: boolPtr(true)},
+		},
+	},
+	"github.com/opentelekomcloud/terraform-provider-opentelekomcloud/opentelekomcloud/common.HasFilledOpt": {
+		KeyArgument: intPtr(1),
+	},
+}
+
+// ImplicitGetter returns the configured getter, configuration has priority over defaults
+func (c *Config) ImplicitGetter(name string) (ImplicitGetter, bool) {
+	if getter, ok := c.ImplicitGetters[name]; ok {
+		return getter, true
+	}
+	getter, ok := DefaultImplicitGetters[name]
+	return getter, ok
+}
+
+// DefaultDeletedHandlers are functions handling errors of deleted resources in `Read`
+var DefaultDeletedHandlers = []string{
+	"github.com/opentelekomcloud/terraform-provider-opentelekomcloud/opentelekomcloud/common.CheckDeleted",
+	"github.com/opentelekomcloud/terraform-provider-opentelekomcloud/opentelekomcloud/common.CheckDeletedDiag",
+}
+
+// IsDeletedHandler checks if the function (`<import path>.<function>`) handles errors of deleted resources
+func (c *Config) IsDeletedHandler(name string) bool {
+	handlers := c.DeletedHandlers
+	if len(handlers) == 0 {
+		handlers = DefaultDeletedHandlers
+	}
+	for _, h := range handlers {
+		if h == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Config describes linter settings
+type Config struct {
+	// Enable lists rules enabled in addition to the default ones
+	Enable []string `yaml:"enable"`
+	// Disable lists rules to be skipped, it has priority over Enable
+	Disable []string `yaml:"disable"`
+	// Validators maps validation functions (`<import path>.<function>`) to types of validated value
+	Validators map[string]string `yaml:"validators"`
+	// WriteOnly lists fields which are never read from the API, either `<field>` or `<resource>.<field>`,
+	// where `<resource>` is the type name, or the generator name for resources missing in the provider maps
+	WriteOnly []string `yaml:"write_only"`
+	// Canonical maps attribute names to their expected declarations, `data.<name>` is used for data sources
+	Canonical map[string]CanonicalField `yaml:"canonical"`
+	// Parity lists rules pairing resources with data sources, same type names are paired by default
+	Parity []ParityRule `yaml:"parity"`
+	// Baseline is the schema snapshot file, relative to the root directory, or `git:<revision>`
+	// the current schema is compared with by the state-upgrade rule
+	Baseline string `yaml:"baseline"`
+	// IntentionalChanges are attribute paths, `<resource>.<path>`, changed since the baseline on purpose,
+	// e.g. removed attributes the state-upgrade rule takes for renamed ones
+	IntentionalChanges []string `yaml:"intentional_changes"`
+	// SchemaImports are schema packages in addition to the upstream SDK v1 and v2 ones, e.g. vendored forks
+	SchemaImports []SchemaImport `yaml:"schema_imports"`
+	// DeletedHandlers are functions (`<import path>.<function>`) handling errors of deleted resources in `Read`,
+	// `common.CheckDeleted` is used by default
+	DeletedHandlers []string `yaml:"deleted_handlers"`
+	// ImplicitGetters maps functions reading `*schema.ResourceData`, `<import path>.<function>` or
+	// `<import path>.<type>.<method>`, to the keys they read, in addition to `DefaultImplicitGetters`
+	ImplicitGetters map[string]ImplicitGetter `yaml:"implicit_getters"`
+}
+
+func DefaultConfig() *Config {
+	return &Config{}
+}
+
+// SchemaGeneration returns SDK generation of the schema package, false if it's not a known schema package
+func (c *Config) SchemaGeneration(path string) (string, bool) {
+	for _, imp := range append(append([]SchemaImport{}, c.SchemaImports...), DefaultSchemaImports...) {
+		if imp.Path == path {
+			return imp.Generation, true
+		}
+	}
+	return "", false
+}
+
+func containsRule(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id || v == AllRules {
+			return true
+		}
+	}
+	return false
+}
+
+// Enabled checks if rule with the given ID should be run
+func (c *Config) Enabled(id string) bool {
+	if containsRule(c.Disable, id) {
+		return false
+	}
+	if containsRule(c.Enable, id) {
+		return true
+	}
+	rule, _ := findRule(id)
+	return rule.Default
+}
+
+// Validate checks that only known rules are configured
+func (c *Config) Validate() error {
+	var unknown []string
+	for _, id := range append(append([]string{}, c.Enable...), c.Disable...) {
+		if _, ok := findRule(id); !ok && id != AllRules {
+			unknown = append(unknown, id)
+		}
+	}
+	if len(unknown) != 0 {
+		return fmt.Errorf("unknown rule(s): %s", strings.Join(unknown, ", "))
+	}
+	for name, typ := range c.Validators {
+		if !ValueTypes.Contains(typ) {
+			return fmt.Errorf("invalid type `%s` of validator `%s`", typ, name)
+		}
+	}
+	for name, fld := range c.Canonical {
+		if fld.Type != "" && !SchemaTypes.Contains(fld.Type) {
+			return fmt.Errorf("invalid type `%s` of canonical attribute `%s`", fld.Type, name)
+		}
+	}
+	for name, getter := range c.ImplicitGetters {
+		for key, fld := range getter.Keys {
+			if fld.Type != "" && !SchemaTypes.Contains(fld.Type) {
+				return fmt.Errorf("invalid type `%s` of key `%s` read by `%s`", fld.Type, key, name)
+			}
+		}
+		if getter.KeyArgument != nil && *getter.KeyArgument < 0 {
+			return fmt.Errorf("invalid key argument %d of `%s`", *getter.KeyArgument, name)
+		}
+	}
+	for _, imp := range c.SchemaImports {
+		if imp.Path == "" {
+			return fmt.Errorf("schema import path is not set")
+		}
+		if imp.Generation != SDKv1 && imp.Generation != SDKv2 {
+			return fmt.Errorf("invalid SDK generation `%s` of schema import `%s`, expected `%s` or `%s`",
+				imp.Generation, imp.Path, SDKv1, SDKv2)
+		}
+	}
+	for _, rule := range c.Parity {
+		if _, err := regexp.Compile(rule.Resource); err != nil {
+			return fmt.Errorf("invalid parity rule `%s`: %w", rule.Resource, err)
+		}
+	}
+	return nil
+}
+
+// IsIntentionalChange checks if the state-incompatible change of the resource attribute is configured as intentional
+func (c *Config) IsIntentionalChange(resource, path string) bool {
+	for _, v := range c.IntentionalChanges {
+		if v == MethodName(resource, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsWriteOnly checks if the field of the resource is configured as write-only
+func (c *Config) IsWriteOnly(resource, field string) bool {
+	for _, v := range c.WriteOnly {
+		if v == field || v == MethodName(resource, field) || v == MethodName("*", field) {
+			return true
+		}
+	}
+	return false
+}
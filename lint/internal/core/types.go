@@ -3,12 +3,39 @@ package core
 import (
 	"fmt"
 	"go/ast"
+	"strings"
 
 	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/set"
 )
 
 const SchemaImportPath = "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
+// SDK generations of the schema packages
+const (
+	SDKv1 = "v1"
+	SDKv2 = "v2"
+)
+
+// SchemaImport is a package declaring `schema.Resource` of the given SDK generation, e.g. a vendored SDK fork
+type SchemaImport struct {
+	Path       string `yaml:"path"`
+	Generation string `yaml:"generation"`
+}
+
+// DefaultSchemaImports are schema packages of the upstream SDK generations
+var DefaultSchemaImports = []SchemaImport{
+	{Path: SchemaImportPath, Generation: SDKv2},
+	{Path: "github.com/hashicorp/terraform-plugin-sdk/helper/schema", Generation: SDKv1},
+}
+
+// IsSchemaPackage checks if the import path looks like an SDK schema package, e.g. of an unknown fork
+func IsSchemaPackage(path string) bool {
+	return strings.HasSuffix(path, "/helper/schema")
+}
+
+// FrameworkImportPath is the root of the terraform-plugin-framework packages
+const FrameworkImportPath = "github.com/hashicorp/terraform-plugin-framework"
+
 type Type interface {
 	String() string
 	Matches(expected string) bool
@@ -65,14 +92,14 @@ type WrapperType struct {
 	Wrapped Type
 }
 
-// knownWrappers describes wrapper types that have known expected types
+// knownWrappers describes wrapper types of the schema package that have known expected types
 var knownWrappers = map[string]string{
-	MethodName(SchemaImportPath, "Set"): "array",
+	"Set": "array",
 }
 
 func (w *WrapperType) Matches(expected string) bool {
-	st, ok := knownWrappers[w.String()]
-	if ok {
+	pkg, name := w.Package(), w.Name()
+	if st, ok := knownWrappers[name]; ok && IsSchemaPackage(pkg) {
 		return st == expected
 	}
 	return w.Wrapped.Matches(expected)
@@ -132,8 +159,9 @@ func (s *StructType) String() string {
 	return MethodName(s.pkg, s.Value)
 }
 
+// Matches is false for any schema type, structs can't be set directly
 func (s *StructType) Matches(string) bool {
-	panic("implement me")
+	return false
 }
 
 func (s *StructType) Name() string {
@@ -0,0 +1,112 @@
+// Package docs parses attribute lists of the resource documentation pages
+package docs
+
+import (
+	"bufio"
+	"bytes"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+)
+
+// pagePaths are documentation page paths relative to the provider root,
+// `%s` is the type name without the provider prefix, e.g. `vpc_v1`
+var pagePaths = map[bool][]string{
+	false: {"docs/resources/%s.md", "website/docs/r/%s.html.markdown", "website/docs/r/%s.html.md"},
+	true:  {"docs/data-sources/%s.md", "website/docs/d/%s.html.markdown", "website/docs/d/%s.html.md"},
+}
+
+var (
+	// bulletRe matches list items like "* `name` - (Optional, ForceNew) Description"
+	bulletRe = regexp.MustCompile("^(\\s*)[*-]\\s+`([A-Za-z0-9_]+)`\\s*-?\\s*(?:\\(([^)]*)\\))?")
+	// blockRe matches nested block introductions like "The `rule` block supports:"
+	blockRe = regexp.MustCompile("^(?:#+\\s*)?(?:[Tt]he\\s+)?`([A-Za-z0-9_]+)`\\s+(?:block|supports|contains)")
+)
+
+// shortName returns type name without the provider prefix
+func shortName(typeName string) string {
+	parts := strings.SplitN(typeName, "_", 2)
+	return parts[len(parts)-1]
+}
+
+// Collect finds and parses documentation pages of the resources, positions of listed attributes
+// are registered in the file set
+func Collect(root string, resources map[string]core.ResourceInfo, fSet *token.FileSet) map[core.ResourceInfo]*core.Doc {
+	result := map[core.ResourceInfo]*core.Doc{}
+	for _, res := range resources {
+		if _, ok := result[res]; ok {
+			continue
+		}
+		for _, pattern := range pagePaths[res.DataSource] {
+			path := filepath.FromSlash(strings.Replace(pattern, "%s", shortName(res.TypeName), 1))
+			content, err := os.ReadFile(filepath.Join(root, path))
+			if err != nil {
+				continue
+			}
+			doc := Parse(content, filepath.Join(root, path), fSet)
+			doc.Path = filepath.ToSlash(path)
+			result[res] = doc
+			break
+		}
+	}
+	return result
+}
+
+// Parse reads "Argument Reference" and "Attributes Reference" lists of the page
+func Parse(content []byte, filename string, fSet *token.FileSet) *core.Doc {
+	file := fSet.AddFile(filename, -1, len(content))
+	file.SetLinesForContent(content)
+
+	doc := &core.Doc{}
+	var (
+		inList, argument bool
+		block, lastKey   string
+	)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if strings.HasPrefix(text, "## ") {
+			title := strings.ToLower(text)
+			argument = strings.Contains(title, "argument")
+			inList = argument || strings.Contains(title, "attribute")
+			block = ""
+			continue
+		}
+		if !inList {
+			continue
+		}
+		if match := blockRe.FindStringSubmatch(text); match != nil {
+			block = match[1]
+			continue
+		}
+		match := bulletRe.FindStringSubmatch(text)
+		if match == nil {
+			continue
+		}
+		attr := core.DocAttribute{
+			Key:      match[2],
+			Pos:      file.LineStart(line),
+			Block:    block,
+			Argument: argument,
+		}
+		if match[1] != "" && lastKey != "" {
+			attr.Block = lastKey // indented items describe the nested block of the previous item
+		} else {
+			lastKey = attr.Key
+		}
+		for _, label := range strings.Split(match[3], ",") {
+			switch strings.TrimSpace(label) {
+			case "Required":
+				attr.Required = true
+			case "Optional":
+				attr.Optional = true
+			}
+		}
+		doc.Attributes = append(doc.Attributes, attr)
+	}
+	return doc
+}
@@ -0,0 +1,42 @@
+package docs
+
+import (
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+)
+
+// examplesDir contains example configurations relative to the provider root
+const examplesDir = "examples"
+
+// CollectExamples reads `.tf` files of the examples directory, the files are registered in the file set
+func CollectExamples(root string, fSet *token.FileSet) []core.HCLConfig {
+	var result []core.HCLConfig
+	_ = filepath.WalkDir(filepath.Join(root, examplesDir), func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".tf") {
+			return nil
+		}
+		content, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return nil
+		}
+		file := fSet.AddFile(path, -1, len(content))
+		file.SetLinesForContent(content)
+		result = append(result, core.HCLConfig{
+			Filename: path,
+			Content:  content,
+			Pos: func(offset int) token.Pos {
+				if offset > file.Size() {
+					offset = file.Size()
+				}
+				return file.Pos(offset)
+			},
+		})
+		return nil
+	})
+	return result
+}
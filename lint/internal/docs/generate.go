@@ -0,0 +1,303 @@
+package docs
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/snapshot"
+)
+
+// Attribute is a single attribute of the documentation page
+type Attribute struct {
+	Name string
+	*snapshot.Attribute
+	// Resource is set for resource attributes, data sources can't be recreated
+	Resource bool
+}
+
+// typeNames are schema types as shown in the documentation
+var typeNames = map[string]string{
+	"TypeBool":   "Boolean",
+	"TypeInt":    "Number",
+	"TypeFloat":  "Number",
+	"TypeString": "String",
+	"TypeList":   "List",
+	"TypeSet":    "Set",
+	"TypeMap":    "Map",
+}
+
+// TypeName returns human-readable type of the attribute, e.g. `List of String`
+func (a Attribute) TypeName() string {
+	name := typeNames[a.Type]
+	switch {
+	case a.Schema != nil:
+		return name + " of Block"
+	case a.Elem != nil && typeNames[a.Elem.Type] != "":
+		return name + " of " + typeNames[a.Elem.Type]
+	}
+	return name
+}
+
+// Labels returns labels shown in parentheses, e.g. `Required, String, ForceNew`
+func (a Attribute) Labels() string {
+	var labels []string
+	switch {
+	case a.Required:
+		labels = append(labels, "Required")
+	case a.Optional:
+		labels = append(labels, "Optional")
+	}
+	if name := a.TypeName(); name != "" {
+		labels = append(labels, name)
+	}
+	if a.ForceNew && a.Resource {
+		labels = append(labels, "ForceNew")
+	}
+	return strings.Join(labels, ", ")
+}
+
+// Line is the list item text, e.g. "`name` - (Required, String) The name. Changing this creates a new resource."
+func (a Attribute) Line() string {
+	parts := []string{fmt.Sprintf("`%s` - (%s)", a.Name, a.Labels())}
+	if a.Description != "" {
+		parts = append(parts, sentence(a.Description))
+	}
+	if a.Default != "" {
+		parts = append(parts, fmt.Sprintf("Defaults to `%s`.", a.Default))
+	}
+	if a.ForceNew && a.Resource {
+		parts = append(parts, "Changing this creates a new resource.")
+	}
+	if a.Deprecated != "" {
+		parts = append(parts, "**Deprecated:** "+sentence(a.Deprecated))
+	}
+	return strings.Join(parts, " ")
+}
+
+// sentence adds the trailing dot
+func sentence(text string) string {
+	text = strings.TrimSpace(text)
+	if strings.HasSuffix(text, ".") {
+		return text
+	}
+	return text + "."
+}
+
+// Block is a nested block with its attributes
+type Block struct {
+	Name       string
+	Attributes []Attribute
+}
+
+// Page is data used to render the documentation page
+type Page struct {
+	// TypeName is the full type name, e.g. `opentelekomcloud_vpc_v1`
+	TypeName   string
+	DataSource bool
+	// Arguments are top-level configurable attributes
+	Arguments []Attribute
+	// Attributes are top-level computed-only attributes
+	Attributes []Attribute
+	Blocks     []Block
+
+	manual map[string]string
+}
+
+func attributes(schema map[string]*snapshot.Attribute, resource bool) []Attribute {
+	keys := make([]string, 0, len(schema))
+	for key := range schema {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := make([]Attribute, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, Attribute{Name: key, Attribute: schema[key], Resource: resource})
+	}
+	return result
+}
+
+// NewPage creates page data of the resource schema
+func NewPage(typeName string, dataSource bool, res *snapshot.Resource) *Page {
+	page := &Page{TypeName: typeName, DataSource: dataSource, manual: map[string]string{}}
+	for _, attr := range attributes(res.Schema, !dataSource) {
+		if attr.Required || attr.Optional {
+			page.Arguments = append(page.Arguments, attr)
+		} else {
+			page.Attributes = append(page.Attributes, attr)
+		}
+	}
+	// blocks are listed breadth-first, in the order of appearance
+	queue := append(append([]Attribute{}, page.Arguments...), page.Attributes...)
+	for len(queue) != 0 {
+		attr := queue[0]
+		queue = queue[1:]
+		if attr.Schema == nil {
+			continue
+		}
+		block := Block{Name: attr.Name, Attributes: attributes(attr.Schema, !dataSource)}
+		page.Blocks = append(page.Blocks, block)
+		queue = append(queue, block.Attributes...)
+	}
+	return page
+}
+
+// Path returns the page path relative to the provider root
+func (p *Page) Path() string {
+	dir := "resources"
+	if p.DataSource {
+		dir = "data-sources"
+	}
+	return fmt.Sprintf("docs/%s/%s.md", dir, shortName(p.TypeName))
+}
+
+const (
+	manualBegin = "<!-- manual:begin %s -->"
+	manualEnd   = "<!-- manual:end %s -->"
+)
+
+var manualRe = regexp.MustCompile(`(?s)<!-- manual:begin ([\w-]+) -->\n(.*?)<!-- manual:end ([\w-]+) -->`)
+
+// parseManual extracts hand-written sections of the existing page
+func parseManual(content []byte) map[string]string {
+	result := map[string]string{}
+	for _, match := range manualRe.FindAllSubmatch(content, -1) {
+		if string(match[1]) == string(match[3]) {
+			result[string(match[1])] = string(match[2])
+		}
+	}
+	return result
+}
+
+// Manual renders hand-written section between markers, the content is kept from the existing page
+func (p *Page) Manual(name string) string {
+	return fmt.Sprintf(manualBegin+"\n%s"+manualEnd, name, p.manual[name], name)
+}
+
+const defaultTemplate = `---
+subcategory: ""
+---
+
+# {{ .TypeName }}
+
+{{ .Manual "description" }}
+
+## Example Usage
+
+{{ .Manual "example" }}
+
+## Argument Reference
+
+The following arguments are supported:
+{{ range .Arguments }}
+* {{ .Line }}
+{{ end -}}
+{{ range .Blocks }}
+The ` + "`{{ .Name }}`" + ` block supports:
+{{ range .Attributes }}
+* {{ .Line }}
+{{ end -}}
+{{ end }}
+## Attributes Reference
+
+In addition to all arguments above, the following attributes are exported:
+
+* ` + "`id`" + ` - ID of the {{ if .DataSource }}data source{{ else }}resource{{ end }}.
+{{ range .Attributes }}
+* {{ .Line }}
+{{ end -}}
+{{ if not .DataSource }}
+{{ .Manual "import" }}
+{{ end -}}
+`
+
+// Generator renders documentation pages using default or user templates
+type Generator struct {
+	// Root is the provider root directory
+	Root string
+	// Templates is the directory with user templates: `resources/<name>.md.tmpl` and `data-sources/<name>.md.tmpl`
+	// for single pages, `resource.md.tmpl` and `data-source.md.tmpl` for all pages of the kind
+	Templates string
+}
+
+// template returns the most specific template of the page
+func (g Generator) template(page *Page) (*template.Template, error) {
+	if g.Templates != "" {
+		kind := "resource"
+		if page.DataSource {
+			kind = "data-source"
+		}
+		candidates := []string{
+			filepath.Join(g.Templates, filepath.Base(filepath.Dir(page.Path())), shortName(page.TypeName)+".md.tmpl"),
+			filepath.Join(g.Templates, kind+".md.tmpl"),
+		}
+		for _, path := range candidates {
+			if _, err := os.Stat(path); err == nil {
+				tmpl, err := template.ParseFiles(path)
+				if err != nil {
+					return nil, fmt.Errorf("error parsing template: %w", err)
+				}
+				return tmpl, nil
+			}
+		}
+	}
+	return template.New("page").Parse(defaultTemplate)
+}
+
+// Render renders the page keeping hand-written sections of the existing page
+func (g Generator) Render(page *Page) ([]byte, error) {
+	existing, err := os.ReadFile(filepath.Join(g.Root, filepath.FromSlash(page.Path())))
+	if err == nil {
+		page.manual = parseManual(existing)
+	}
+	tmpl, err := g.template(page)
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, page); err != nil {
+		return nil, fmt.Errorf("error rendering %s: %w", page.Path(), err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Generate renders pages of all resources and data sources and returns paths of changed pages,
+// pages are written only if `write` is set
+func (g Generator) Generate(schemas *snapshot.Provider, write bool) ([]string, error) {
+	var pages []*Page
+	for name, res := range schemas.Resources {
+		pages = append(pages, NewPage(name, false, res))
+	}
+	for name, res := range schemas.DataSources {
+		pages = append(pages, NewPage(name, true, res))
+	}
+	sort.Slice(pages, func(i, j int) bool { return pages[i].Path() < pages[j].Path() })
+
+	var changed []string
+	for _, page := range pages {
+		content, err := g.Render(page)
+		if err != nil {
+			return nil, err
+		}
+		path := filepath.Join(g.Root, filepath.FromSlash(page.Path()))
+		if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, content) {
+			continue
+		}
+		changed = append(changed, page.Path())
+		if !write {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			return nil, fmt.Errorf("error creating docs directory: %w", err)
+		}
+		if err := os.WriteFile(path, content, 0o644); err != nil { //nolint:gosec
+			return nil, fmt.Errorf("error writing %s: %w", page.Path(), err)
+		}
+	}
+	return changed, nil
+}
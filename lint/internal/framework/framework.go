@@ -0,0 +1,257 @@
+// Package framework collects and validates resources and data sources implemented with terraform-plugin-framework
+package framework
+
+import (
+	"go/ast"
+	"go/token"
+	"path"
+	"strconv"
+
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/parser"
+	"golang.org/x/tools/go/packages"
+)
+
+// Import paths of the framework packages
+const (
+	resourceImportPath   = core.FrameworkImportPath + "/resource"
+	dataSourceImportPath = core.FrameworkImportPath + "/datasource"
+	typesImportPath      = core.FrameworkImportPath + "/types"
+	pathImportPath       = core.FrameworkImportPath + "/path"
+)
+
+// schemaImportPaths are packages declaring schema attributes of resources and data sources
+var schemaImportPaths = []string{
+	resourceImportPath + "/schema",
+	dataSourceImportPath + "/schema",
+}
+
+// importNames maps import paths of the file to the names used in the file
+func importNames(file *ast.File) map[string]string {
+	result := map[string]string{}
+	for _, imp := range file.Imports {
+		val, _ := core.UnwrapString(imp.Path)
+		if imp.Name != nil {
+			result[val] = imp.Name.Name
+			continue
+		}
+		result[val] = path.Base(val)
+	}
+	return result
+}
+
+// isPackageSelector checks if the expression is `<pkg>.<name>` of the package imported with the name
+func isPackageSelector(expr ast.Expr, pkgName string) (string, bool) {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || pkgName == "" {
+		return "", false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	if !ok || x.Name != pkgName || x.Obj != nil {
+		return "", false
+	}
+	return sel.Sel.Name, true
+}
+
+// receiverName returns the name of the receiver type, e.g. `vpcResource` for `(r *vpcResource)`
+func receiverName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) != 1 {
+		return ""
+	}
+	typ := fn.Recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	if ident, ok := typ.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// schemaMethod checks if the function is `Schema(context.Context, resource.SchemaRequest, *resource.SchemaResponse)`
+// and returns the data source flag
+func schemaMethod(fn *ast.FuncDecl, imports map[string]string) (bool, bool) {
+	if fn.Name.Name != "Schema" || receiverName(fn) == "" {
+		return false, false
+	}
+	params := fn.Type.Params.List
+	var types []ast.Expr
+	for _, p := range params {
+		n := len(p.Names)
+		if n == 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			types = append(types, p.Type)
+		}
+	}
+	if len(types) != 3 {
+		return false, false
+	}
+	if name, ok := isPackageSelector(types[1], imports[resourceImportPath]); ok && name == "SchemaRequest" {
+		return false, true
+	}
+	if name, ok := isPackageSelector(types[1], imports[dataSourceImportPath]); ok && name == "SchemaRequest" {
+		return true, true
+	}
+	return false, false
+}
+
+// Collect finds types implementing `resource.Resource` and `datasource.DataSource` and extracts their schemas
+func Collect(pkgs []*packages.Package, fSet *token.FileSet) []*core.FrameworkResource {
+	var result []*core.FrameworkResource
+	for _, pkg := range pkgs {
+		if parser.IsTestVariant(pkg) {
+			continue
+		}
+		methods := map[string][]*ast.FuncDecl{}
+		var found []*core.FrameworkResource
+		for _, file := range pkg.Syntax {
+			imports := importNames(file)
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Body == nil {
+					continue
+				}
+				recv := receiverName(fn)
+				if recv == "" {
+					continue
+				}
+				methods[recv] = append(methods[recv], fn)
+				dataSource, ok := schemaMethod(fn, imports)
+				if !ok {
+					continue
+				}
+				found = append(found, &core.FrameworkResource{
+					Type:       recv,
+					DataSource: dataSource,
+					Pkg:        pkg,
+					Fset:       fSet,
+					Pos:        fn.Pos(),
+					Schema:     schemaAttributes(fn, imports),
+				})
+			}
+		}
+		for _, res := range found {
+			res.Methods = methods[res.Type]
+		}
+		result = append(result, found...)
+	}
+	return result
+}
+
+// schemaAttributes extracts attributes from `resp.Schema = schema.Schema{...}` assignment
+func schemaAttributes(fn *ast.FuncDecl, imports map[string]string) map[string]*core.FrameworkAttribute {
+	var schemaPkg string
+	for _, importPath := range schemaImportPaths {
+		if name, ok := imports[importPath]; ok {
+			schemaPkg = name
+			break
+		}
+	}
+	var result map[string]*core.FrameworkAttribute
+	ast.Inspect(fn.Body, func(node ast.Node) bool {
+		assign, ok := node.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			return true
+		}
+		sel, ok := assign.Lhs[0].(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Schema" {
+			return true
+		}
+		lit, ok := assign.Rhs[0].(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+		if name, ok := isPackageSelector(lit.Type, schemaPkg); !ok || name != "Schema" {
+			return true
+		}
+		result = objectAttributes(lit, schemaPkg)
+		return false
+	})
+	return result
+}
+
+// objectAttributes extracts `Attributes` and `Blocks` of the schema or of the nested object
+func objectAttributes(lit *ast.CompositeLit, schemaPkg string) map[string]*core.FrameworkAttribute {
+	result := map[string]*core.FrameworkAttribute{}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok || (key.Name != "Attributes" && key.Name != "Blocks") {
+			continue
+		}
+		attrs, ok := kv.Value.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+		for _, attrElt := range attrs.Elts {
+			attrKV, ok := attrElt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			name, ok := stringLit(attrKV.Key)
+			if !ok {
+				continue
+			}
+			attr := &core.FrameworkAttribute{Name: name, Pos: attrKV.Key.Pos()}
+			if value, ok := attrKV.Value.(*ast.CompositeLit); ok {
+				loadAttribute(attr, value, schemaPkg)
+			}
+			result[name] = attr
+		}
+	}
+	return result
+}
+
+// loadAttribute sets the kind, the flags and nested attributes of the attribute
+func loadAttribute(attr *core.FrameworkAttribute, lit *ast.CompositeLit, schemaPkg string) {
+	attr.Kind, _ = isPackageSelector(lit.Type, schemaPkg)
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		switch key.Name {
+		case "Required":
+			attr.Required = isTrue(kv.Value)
+		case "Optional":
+			attr.Optional = isTrue(kv.Value)
+		case "Computed":
+			attr.Computed = isTrue(kv.Value)
+		case "NestedObject":
+			if nested, ok := kv.Value.(*ast.CompositeLit); ok {
+				attr.Nested = objectAttributes(nested, schemaPkg)
+			}
+		case "Attributes", "Blocks": // single nested attributes and blocks
+			nested := objectAttributes(&ast.CompositeLit{Elts: []ast.Expr{kv}}, schemaPkg)
+			if attr.Nested == nil {
+				attr.Nested = map[string]*core.FrameworkAttribute{}
+			}
+			for name, a := range nested {
+				attr.Nested[name] = a
+			}
+		}
+	}
+}
+
+func isTrue(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "true"
+}
+
+func stringLit(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	return value, err == nil
+}
@@ -0,0 +1,353 @@
+package framework
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/generators"
+)
+
+// valueTypes are `types` package types of the model fields by the schema type name
+var valueTypes = map[string]string{
+	"StringAttribute":       "String",
+	"BoolAttribute":         "Bool",
+	"Int64Attribute":        "Int64",
+	"Int32Attribute":        "Int32",
+	"Float64Attribute":      "Float64",
+	"Float32Attribute":      "Float32",
+	"NumberAttribute":       "Number",
+	"DynamicAttribute":      "Dynamic",
+	"ListAttribute":         "List",
+	"ListNestedAttribute":   "List",
+	"ListNestedBlock":       "List",
+	"SetAttribute":          "Set",
+	"SetNestedAttribute":    "Set",
+	"SetNestedBlock":        "Set",
+	"MapAttribute":          "Map",
+	"MapNestedAttribute":    "Map",
+	"ObjectAttribute":       "Object",
+	"SingleNestedAttribute": "Object",
+	"SingleNestedBlock":     "Object",
+}
+
+// isValueType checks if the name is one of the `types` package value types
+func isValueType(name string) bool {
+	for _, t := range valueTypes {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+// stateFields are request and response fields holding values of the schema
+var stateFields = map[string]bool{
+	"Plan":   true,
+	"State":  true,
+	"Config": true,
+}
+
+func ruleError(res *core.FrameworkResource, rule string, pos token.Pos, format string, args ...interface{}) error {
+	msg := fmt.Sprintf(format, args...)
+	return fmt.Errorf("%s - %s [%s]", generators.Position(res.Fset, pos).String(), msg, rule)
+}
+
+// stateCall returns the method name of `<req|resp>.<Plan|State|Config>.<method>(...)` call
+func stateCall(call *ast.CallExpr) string {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	field, ok := sel.X.(*ast.SelectorExpr)
+	if !ok || !stateFields[field.Sel.Name] {
+		return ""
+	}
+	return sel.Sel.Name
+}
+
+// Validate checks models and attribute paths used by methods of the framework resources
+func Validate(resources []*core.FrameworkResource, config *core.Config) error {
+	mErr := &multierror.Error{}
+	for _, res := range resources {
+		if res.Schema == nil {
+			continue // schema is not a literal
+		}
+		if config.Enabled(core.RuleFrameworkModels) {
+			mErr = multierror.Append(mErr, validateModels(res))
+		}
+		if config.Enabled(core.RuleFrameworkPaths) {
+			mErr = multierror.Append(mErr, validatePaths(res))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// modelName returns the name of the struct type of the variable passed to `Get` or `Set`
+func modelName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.UnaryExpr:
+		if e.Op == token.AND {
+			return modelName(e.X)
+		}
+	case *ast.StarExpr:
+		return modelName(e.X)
+	case *ast.CompositeLit:
+		if ident, ok := e.Type.(*ast.Ident); ok {
+			return ident.Name
+		}
+	case *ast.Ident:
+		if e.Obj == nil {
+			return ""
+		}
+		switch decl := e.Obj.Decl.(type) {
+		case *ast.ValueSpec:
+			if decl.Type != nil {
+				if ident, ok := decl.Type.(*ast.Ident); ok {
+					return ident.Name
+				}
+				return modelName(decl.Type)
+			}
+			for i, name := range decl.Names {
+				if name.Obj == e.Obj && i < len(decl.Values) {
+					return modelName(decl.Values[i])
+				}
+			}
+		case *ast.AssignStmt:
+			for i, lhs := range decl.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok && ident.Obj == e.Obj && len(decl.Lhs) == len(decl.Rhs) {
+					return modelName(decl.Rhs[i])
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// model is a struct type declared in the package of the resource
+type model struct {
+	name    string
+	st      *ast.StructType
+	imports map[string]string
+}
+
+// findModel finds the struct type declaration by name
+func findModel(res *core.FrameworkResource, name string) *model {
+	for _, file := range res.Pkg.Syntax {
+		obj := file.Scope.Lookup(name)
+		if obj == nil {
+			continue
+		}
+		spec, ok := obj.Decl.(*ast.TypeSpec)
+		if !ok {
+			return nil
+		}
+		st, ok := spec.Type.(*ast.StructType)
+		if !ok {
+			return nil
+		}
+		return &model{name: name, st: st, imports: importNames(file)}
+	}
+	return nil
+}
+
+// validateModels checks structs read with `Get` and written with `Set` against the schema
+func validateModels(res *core.FrameworkResource) error {
+	mErr := &multierror.Error{}
+	checked := map[string]bool{}
+	for _, fn := range res.Methods {
+		ast.Inspect(fn.Body, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok || len(call.Args) != 2 {
+				return true
+			}
+			if method := stateCall(call); method != "Get" && method != "Set" {
+				return true
+			}
+			name := modelName(call.Args[1])
+			if name == "" || checked[name] {
+				return true
+			}
+			checked[name] = true
+			if m := findModel(res, name); m != nil {
+				mErr = multierror.Append(mErr, checkModel(res, m, res.Schema, ""))
+			}
+			return true
+		})
+	}
+	return mErr.ErrorOrNil()
+}
+
+// nestedModel returns the name of the local struct type used for nested objects, e.g. `[]ruleModel`
+func nestedModel(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.ArrayType:
+		return nestedModel(e.Elt)
+	case *ast.StarExpr:
+		return nestedModel(e.X)
+	case *ast.Ident:
+		return e.Name
+	}
+	return ""
+}
+
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// checkModel checks `tfsdk` tags and field types of the model, nested models are checked recursively
+func checkModel(res *core.FrameworkResource, m *model, attrs map[string]*core.FrameworkAttribute, prefix string) error {
+	mErr := &multierror.Error{}
+	tagged := map[string]bool{}
+	for _, fld := range m.st.Fields.List {
+		if fld.Tag == nil || len(fld.Names) == 0 {
+			continue
+		}
+		tag, err := strconv.Unquote(fld.Tag.Value)
+		if err != nil {
+			continue
+		}
+		key, ok := reflect.StructTag(tag).Lookup("tfsdk")
+		if !ok || key == "-" {
+			continue
+		}
+		tagged[key] = true
+		fieldName := m.name + "." + fld.Names[0].Name
+		attr, ok := attrs[key]
+		if !ok {
+			mErr = multierror.Append(mErr, ruleError(res, core.RuleFrameworkModels, fld.Tag.Pos(),
+				"`%s` tag `%s` doesn't match any attribute of `%s`", fieldName, joinPath(prefix, key), res.Type))
+			continue
+		}
+		if typeName, ok := isPackageSelector(fld.Type, m.imports[typesImportPath]); ok {
+			if expected := valueTypes[attr.Kind]; expected != "" && isValueType(typeName) && typeName != expected {
+				mErr = multierror.Append(mErr, ruleError(res, core.RuleFrameworkModels, fld.Type.Pos(),
+					"`%s` is `types.%s`, but `%s` is `%s`", fieldName, typeName, joinPath(prefix, key), attr.Kind))
+			}
+			continue
+		}
+		if attr.Nested == nil {
+			continue
+		}
+		if nested := findModel(res, nestedModel(fld.Type)); nested != nil {
+			mErr = multierror.Append(mErr, checkModel(res, nested, attr.Nested, joinPath(prefix, key)))
+		}
+	}
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !tagged[name] {
+			mErr = multierror.Append(mErr, ruleError(res, core.RuleFrameworkModels, m.st.Pos(),
+				"`%s` has no field for attribute `%s` of `%s`", m.name, joinPath(prefix, name), res.Type))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// pathStep is a single step of the attribute path, e.g. `AtName("port")`
+type pathStep struct {
+	Method string
+	Name   string
+}
+
+// pathSteps returns steps of `path.Root("a").AtName("b")` expression, nil if the path is not a call chain
+func pathSteps(expr ast.Expr, pathPkg string) []pathStep {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	step := pathStep{Method: sel.Sel.Name}
+	if len(call.Args) == 1 {
+		step.Name, _ = stringLit(call.Args[0])
+	}
+	if x, ok := sel.X.(*ast.Ident); ok && x.Name == pathPkg && x.Obj == nil {
+		if step.Method != "Root" || step.Name == "" {
+			return nil
+		}
+		return []pathStep{step}
+	}
+	if !strings.HasPrefix(step.Method, "At") {
+		return nil
+	}
+	parent := pathSteps(sel.X, pathPkg)
+	if parent == nil {
+		return nil
+	}
+	return append(parent, step)
+}
+
+// validatePaths checks attribute paths of `SetAttribute` and `GetAttribute` calls
+func validatePaths(res *core.FrameworkResource) error {
+	mErr := &multierror.Error{}
+	for _, fn := range res.Methods {
+		file := fileOf(res, fn.Pos())
+		if file == nil {
+			continue
+		}
+		pathPkg := importNames(file)[pathImportPath]
+		ast.Inspect(fn.Body, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok || len(call.Args) != 3 {
+				return true
+			}
+			if method := stateCall(call); method != "SetAttribute" && method != "GetAttribute" {
+				return true
+			}
+			steps := pathSteps(call.Args[1], pathPkg)
+			if steps == nil {
+				return true
+			}
+			mErr = multierror.Append(mErr, checkPath(res, steps, call.Args[1].Pos()))
+			return true
+		})
+	}
+	return mErr.ErrorOrNil()
+}
+
+// checkPath follows the path steps through the schema, attributes without known nested objects end the check
+func checkPath(res *core.FrameworkResource, steps []pathStep, pos token.Pos) error {
+	attrs := res.Schema
+	var names []string
+	for _, step := range steps {
+		if step.Method != "Root" && step.Method != "AtName" {
+			continue // list index, set value or map key
+		}
+		if attrs == nil {
+			return nil
+		}
+		names = append(names, step.Name)
+		attr, ok := attrs[step.Name]
+		if !ok {
+			return ruleError(res, core.RuleFrameworkPaths, pos,
+				"path `%s` doesn't match any attribute of `%s`", strings.Join(names, "."), res.Type)
+		}
+		attrs = attr.Nested
+	}
+	return nil
+}
+
+// fileOf returns the file of the resource package containing the position
+func fileOf(res *core.FrameworkResource, pos token.Pos) *ast.File {
+	for _, file := range res.Pkg.Syntax {
+		if file.Pos() <= pos && pos <= file.End() {
+			return file
+		}
+	}
+	return nil
+}
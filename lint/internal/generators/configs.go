@@ -0,0 +1,230 @@
+package generators
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/set"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// metaArguments can be used in any resource or data source
+var metaArguments = set.StringSetFromSlice([]string{"count", "for_each", "provider", "depends_on"})
+
+// metaBlocks can be used in any resource, content of the blocks is not validated
+var metaBlocks = set.StringSetFromSlice([]string{"lifecycle", "provisioner", "connection", "timeouts"})
+
+// configChecker validates single configuration
+type configChecker struct {
+	gen    *Generator // used to report errors, all generators share the file set
+	config core.HCLConfig
+	errs   *multierror.Error
+}
+
+func (c *configChecker) report(rng hcl.Range, format string, args ...interface{}) {
+	c.errs = multierror.Append(c.errs, c.gen.ruleError(core.RuleExampleConfigs, c.config.Pos(rng.Start.Byte), format, args...))
+}
+
+// containsAny checks if the range contains any of the offsets
+func containsAny(rng hcl.Range, offsets []int) bool {
+	for _, offset := range offsets {
+		if offset >= rng.Start.Byte && offset < rng.End.Byte {
+			return true
+		}
+	}
+	return false
+}
+
+// providerPrefix returns the type name prefix of the provider resources, e.g. `opentelekomcloud_`
+func providerPrefix(typeName string) string {
+	return strings.SplitN(typeName, "_", 2)[0] + "_"
+}
+
+func validateExampleConfigs(gens []*Generator, _ *core.Config) error {
+	if len(gens) == 0 {
+		return nil
+	}
+	byType := map[core.ResourceInfo]*Generator{}
+	prefixes := map[string]bool{}
+	for _, gen := range gens {
+		if gen.Resource.TypeName != "" {
+			byType[gen.Resource] = gen
+			prefixes[providerPrefix(gen.Resource.TypeName)] = true
+		}
+	}
+	mErr := &multierror.Error{}
+	for _, config := range gens[0].Project.Configs {
+		c := &configChecker{gen: gens[0], config: config, errs: &multierror.Error{}}
+		file, diags := hclsyntax.ParseConfig(config.Content, config.Filename, hcl.InitialPos)
+		if diags.HasErrors() {
+			for _, diag := range diags.Errs() {
+				if d, ok := diag.(*hcl.Diagnostic); ok && d.Subject != nil {
+					c.report(*d.Subject, "invalid configuration: %s", d.Detail)
+				}
+			}
+			mErr = multierror.Append(mErr, c.errs.ErrorOrNil())
+			continue
+		}
+		body, ok := file.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		for _, block := range body.Blocks {
+			if (block.Type != "resource" && block.Type != "data") || len(block.Labels) != 2 {
+				continue
+			}
+			info := core.ResourceInfo{TypeName: block.Labels[0], DataSource: block.Type == "data"}
+			gen, ok := byType[info]
+			if !ok {
+				if prefixes[providerPrefix(info.TypeName)] {
+					kind := "resource"
+					if info.DataSource {
+						kind = "data source"
+					}
+					c.report(block.LabelRanges[0], "unknown %s type `%s`", kind, info.TypeName)
+				}
+				continue
+			}
+			if gen.Schema == nil {
+				continue
+			}
+			c.gen = gen
+			c.checkBody(block.Body, gen.Schema, fmt.Sprintf("%s.%s", info, block.Labels[1]), true)
+		}
+		mErr = multierror.Append(mErr, c.errs.ErrorOrNil())
+	}
+	return mErr.ErrorOrNil()
+}
+
+// checkBody validates arguments and nested blocks of the resource or the block
+func (c *configChecker) checkBody(body *hclsyntax.Body, schema map[string]*Field, address string, topLevel bool) {
+	attrs := make([]*hclsyntax.Attribute, 0, len(body.Attributes))
+	for _, attr := range body.Attributes {
+		attrs = append(attrs, attr)
+	}
+	sort.Slice(attrs, func(i, j int) bool { return attrs[i].SrcRange.Start.Byte < attrs[j].SrcRange.Start.Byte })
+	for _, attr := range attrs {
+		name := attr.Name
+		fld, ok := schema[name]
+		if !ok {
+			if !topLevel || !metaArguments.Contains(name) {
+				c.report(attr.NameRange, "`%s`: unsupported argument `%s`", address, name)
+			}
+			continue
+		}
+		if fld == nil {
+			continue
+		}
+		if !fld.Required && !fld.Optional {
+			c.report(attr.NameRange, "`%s`: argument `%s` is computed-only and can't be set", address, name)
+			continue
+		}
+		if containsAny(attr.Expr.Range(), c.config.Placeholders) {
+			continue // value is inserted by `fmt.Sprintf`
+		}
+		if msg := literalTypeMismatch(attr.Expr, fld); msg != "" {
+			c.report(attr.Expr.Range(), "`%s`: invalid value of `%s`: %s", address, name, msg)
+		}
+	}
+
+	present := map[string]bool{}
+	for name := range body.Attributes {
+		present[name] = true
+	}
+	for _, block := range body.Blocks {
+		name := block.Type
+		if name == "dynamic" && len(block.Labels) == 1 {
+			name = block.Labels[0]
+		}
+		present[name] = true
+		fld, ok := schema[name]
+		if !ok {
+			if !topLevel || !metaBlocks.Contains(name) {
+				c.report(block.TypeRange, "`%s`: unsupported block `%s`", address, name)
+			}
+			continue
+		}
+		if fld == nil {
+			continue
+		}
+		if fld.Schema == nil {
+			c.report(block.TypeRange, "`%s`: `%s` is an argument, not a block", address, name)
+			continue
+		}
+		if block.Type != "dynamic" {
+			c.checkBody(block.Body, fld.Schema, address+"."+name, false)
+		}
+	}
+
+	if containsAny(body.SrcRange, c.config.Interpolated) {
+		return // arguments can be inserted by `fmt.Sprintf`
+	}
+	for _, key := range sortedKeys(schema) {
+		if fld := schema[key]; fld != nil && fld.Required && !present[key] {
+			c.report(body.SrcRange, "`%s`: missing required argument `%s`", address, key)
+		}
+	}
+}
+
+// literalValue returns value of the literal expression, e.g. `"text"`, `1` or `true`
+func literalValue(expr hclsyntax.Expression) (cty.Value, bool) {
+	switch e := expr.(type) {
+	case *hclsyntax.LiteralValueExpr:
+		return e.Val, true
+	case *hclsyntax.TemplateExpr:
+		if e.IsStringLiteral() {
+			return e.Parts[0].(*hclsyntax.LiteralValueExpr).Val, true
+		}
+	}
+	return cty.NilVal, false
+}
+
+// isCollection checks if the schema type is a list, a set or a map
+func isCollection(typ string) bool {
+	return typeMapping[typ] == "array" || typeMapping[typ] == "map"
+}
+
+// literalTypeMismatch describes obvious mismatch of the literal value and the field type
+func literalTypeMismatch(expr hclsyntax.Expression, fld *Field) string {
+	if typeMapping[fld.Type] == "" {
+		return ""
+	}
+	switch expr.(type) {
+	case *hclsyntax.TupleConsExpr, *hclsyntax.ObjectConsExpr:
+		if !isCollection(fld.Type) {
+			return fmt.Sprintf("collection is used for `%s` field", fld.Type)
+		}
+		return ""
+	}
+	val, ok := literalValue(expr)
+	if !ok || val.IsNull() || !val.IsKnown() {
+		return ""
+	}
+	if isCollection(fld.Type) {
+		return fmt.Sprintf("%s is used for `%s` field", val.Type().FriendlyName(), fld.Type)
+	}
+	switch fld.Type {
+	case "TypeInt", "TypeFloat":
+		if val.Type() == cty.Bool {
+			return fmt.Sprintf("bool is used for `%s` field", fld.Type)
+		}
+		if val.Type() == cty.String {
+			if _, err := cty.ParseNumberVal(val.AsString()); err != nil {
+				return fmt.Sprintf("`%s` is not a number", val.AsString())
+			}
+		}
+	case "TypeBool":
+		if val.Type() == cty.Number {
+			return "number is used for `TypeBool` field"
+		}
+		if val.Type() == cty.String && val.AsString() != "true" && val.AsString() != "false" {
+			return fmt.Sprintf("`%s` is not a bool", val.AsString())
+		}
+	}
+	return ""
+}
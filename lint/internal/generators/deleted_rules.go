@@ -0,0 +1,247 @@
+package generators
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+)
+
+// notFoundError is the error type returned by the SDK for missing resources
+const notFoundError = "github.com/opentelekomcloud/gophertelekomcloud.ErrDefault404"
+
+// sdkCall is the first SDK call of the operation with its error check
+type sdkCall struct {
+	Call   *ast.CallExpr
+	ErrVar string
+	// Branch is the body of `if err != nil` following the call, nil if the error is not checked or not returned
+	Branch *ast.BlockStmt
+}
+
+// ValidateDeletedHandling checks that `Read` clears ID of the resource deleted outside Terraform
+// and `Delete` doesn't fail if the resource is already deleted
+func (g Generator) ValidateDeletedHandling() error {
+	if g.Resource.DataSource {
+		return nil // data sources have to fail on missing resources
+	}
+	mErr := &multierror.Error{}
+	if read := g.Operations["Read"]; read != nil && read.Body != nil {
+		dName := getDName(read)
+		if call := firstSDKCall(read, dName, "Get"); call != nil && call.Branch != nil &&
+			!g.handlesDeleted(call.Branch, call.ErrVar) && !clearsID(call.Branch, dName) {
+			mErr = multierror.Append(mErr, g.ruleError(core.RuleDeletedResources, call.Branch.Pos(),
+				"`Read` returns error of `%s` without clearing ID of the deleted resource, "+
+					"use `common.CheckDeleted` or `%s.SetId(\"\")`", callName(call.Call), dName))
+		}
+	}
+	if del := g.Operations["Delete"]; del != nil && del.Body != nil {
+		if call := firstSDKCall(del, getDName(del), "Delete"); call != nil && call.Branch != nil &&
+			!g.handlesDeleted(call.Branch, call.ErrVar) && !g.checksNotFound(call.Branch) {
+			mErr = multierror.Append(mErr, g.ruleError(core.RuleDeletedResources, call.Branch.Pos(),
+				"`Delete` returns error of `%s` without tolerating `golangsdk.ErrDefault404`, "+
+					"deletion of already deleted resource fails", callName(call.Call)))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// packageCall returns the innermost call of `<pkg>.<function>(...).Extract()` chain and the package name
+func packageCall(call *ast.CallExpr) (*ast.SelectorExpr, string) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil, ""
+	}
+	switch x := sel.X.(type) {
+	case *ast.Ident:
+		if x.Obj != nil {
+			return nil, "" // method of a local variable
+		}
+		return sel, x.Name
+	case *ast.CallExpr:
+		return packageCall(x)
+	}
+	return nil, ""
+}
+
+// isSDKCall checks if the call is `<pkg>.<method>(...)`, possibly followed by `.Extract()` of the result
+func isSDKCall(expr ast.Expr, dName, method string) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, pkgName := packageCall(call)
+	return sel != nil && sel.Sel.Name == method && pkgName != dName
+}
+
+// callName returns `<pkg>.<function>` of the SDK call, e.g. `servers.Get` for `servers.Get(c, id).Extract()`
+func callName(call *ast.CallExpr) string {
+	sel, pkgName := packageCall(call)
+	if sel == nil {
+		return "SDK call"
+	}
+	return pkgName + "." + sel.Sel.Name
+}
+
+// assignedErr returns the error variable assigned with the SDK call result, e.g. `err` of `v, err := ...`
+func assignedErr(stmt ast.Stmt, dName, method string) (*ast.CallExpr, string) {
+	assign, ok := stmt.(*ast.AssignStmt)
+	if !ok || len(assign.Rhs) != 1 || !isSDKCall(assign.Rhs[0], dName, method) {
+		return nil, ""
+	}
+	ident, ok := assign.Lhs[len(assign.Lhs)-1].(*ast.Ident)
+	if !ok || ident.Name == "_" {
+		return nil, ""
+	}
+	return assign.Rhs[0].(*ast.CallExpr), ident.Name
+}
+
+// isErrCheck checks if the condition is `err != nil`
+func isErrCheck(cond ast.Expr, errVar string) bool {
+	bin, ok := cond.(*ast.BinaryExpr)
+	if !ok || bin.Op != token.NEQ {
+		return false
+	}
+	x, ok := bin.X.(*ast.Ident)
+	y, okY := bin.Y.(*ast.Ident)
+	return ok && okY && x.Name == errVar && y.Name == "nil"
+}
+
+// firstSDKCall finds the first `<pkg>.<method>` SDK call assigned to an error variable in the function,
+// calls in function literals are skipped. The error check is searched in following statements of the same block
+func firstSDKCall(fn *ast.FuncDecl, dName, method string) *sdkCall {
+	var result *sdkCall
+	ast.Inspect(fn.Body, func(node ast.Node) bool {
+		if result != nil {
+			return false
+		}
+		var list []ast.Stmt
+		switch n := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.IfStmt:
+			if n.Init == nil {
+				return true
+			}
+			if call, errVar := assignedErr(n.Init, dName, method); call != nil {
+				result = &sdkCall{Call: call, ErrVar: errVar}
+				if isErrCheck(n.Cond, errVar) {
+					result.Branch = n.Body
+				}
+				return false
+			}
+			return true
+		case *ast.BlockStmt:
+			list = n.List
+		case *ast.CaseClause:
+			list = n.Body
+		case *ast.CommClause:
+			list = n.Body
+		default:
+			return true
+		}
+		for i, stmt := range list {
+			call, errVar := assignedErr(stmt, dName, method)
+			if call == nil {
+				continue
+			}
+			result = &sdkCall{Call: call, ErrVar: errVar, Branch: errBranch(list[i+1:], errVar)}
+			return false
+		}
+		return true
+	})
+	if result != nil && !hasReturn(result.Branch) {
+		result.Branch = nil
+	}
+	return result
+}
+
+// hasReturn checks if the block contains `return` statement outside function literals
+func hasReturn(block *ast.BlockStmt) bool {
+	if block == nil {
+		return false
+	}
+	found := false
+	ast.Inspect(block, func(node ast.Node) bool {
+		switch node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// errBranch finds `if err != nil` in the statements before the error variable is reassigned
+func errBranch(stmts []ast.Stmt, errVar string) *ast.BlockStmt {
+	for _, stmt := range stmts {
+		switch s := stmt.(type) {
+		case *ast.IfStmt:
+			if s.Init == nil && isErrCheck(s.Cond, errVar) {
+				return s.Body
+			}
+		case *ast.AssignStmt:
+			for _, lhs := range s.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok && ident.Name == errVar {
+					return nil
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// handlesDeleted checks if the error is passed to one of the configured handlers
+func (g Generator) handlesDeleted(branch *ast.BlockStmt, errVar string) bool {
+	found := false
+	ast.Inspect(branch, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || found {
+			return !found
+		}
+		if !g.Config.IsDeletedHandler(g.funcFullName(call.Fun)) {
+			return true
+		}
+		for _, arg := range call.Args {
+			if ident, ok := arg.(*ast.Ident); ok && ident.Name == errVar {
+				found = true
+			}
+		}
+		return true
+	})
+	return found
+}
+
+// clearsID checks if the branch contains `d.SetId("")`
+func clearsID(branch *ast.BlockStmt, dName string) bool {
+	found := false
+	ast.Inspect(branch, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if ok && isDataMethod(call.Fun, dName, "SetId") && len(call.Args) == 1 {
+			if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Value == `""` {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// checksNotFound checks if the branch uses `golangsdk.ErrDefault404`, e.g. in a type assertion
+func (g Generator) checksNotFound(branch *ast.BlockStmt) bool {
+	found := false
+	ast.Inspect(branch, func(node ast.Node) bool {
+		sel, ok := node.(*ast.SelectorExpr)
+		if !ok || found {
+			return !found
+		}
+		x, ok := sel.X.(*ast.Ident)
+		if ok && x.Obj == nil && core.MethodName(g.absoluteImport(x.Name, sel, g.Pkg), sel.Sel.Name) == notFoundError {
+			found = true
+		}
+		return true
+	})
+	return found
+}
@@ -0,0 +1,123 @@
+package generators
+
+import (
+	"sort"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+)
+
+// findBlock searches the nested block by name at any depth of the schema
+func findBlock(schema map[string]*Field, name string) *Field {
+	if fld, ok := schema[name]; ok && fld != nil && fld.Schema != nil {
+		return fld
+	}
+	for _, fld := range schema {
+		if fld == nil || fld.Schema == nil {
+			continue
+		}
+		if found := findBlock(fld.Schema, name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// ValidateDocs checks that the documentation page lists all attributes, including attributes of
+// nested blocks having a section on the page, doesn't list attributes missing in the schema
+// and labels arguments the same way as the schema
+func (g Generator) ValidateDocs() error {
+	doc := g.Project.Docs[g.Resource]
+	if g.Resource.TypeName == "" || g.Schema == nil || doc == nil {
+		return nil
+	}
+	mErr := &multierror.Error{}
+	documented := map[string]bool{}
+	// blocks are documented attributes of the nested block sections, by the block name
+	blocks := map[string]map[string]bool{}
+	for _, attr := range doc.Attributes {
+		schema := g.Schema
+		if attr.Block != "" {
+			block := findBlock(g.Schema, attr.Block)
+			if block == nil {
+				continue // e.g. `timeouts` block
+			}
+			schema = block.Schema
+			if blocks[attr.Block] == nil {
+				blocks[attr.Block] = map[string]bool{}
+			}
+			blocks[attr.Block][attr.Key] = true
+		} else {
+			documented[attr.Key] = true
+		}
+		fld, ok := schema[attr.Key]
+		if !ok {
+			if attr.Key == idAttribute && attr.Block == "" {
+				continue
+			}
+			mErr = multierror.Append(mErr, g.ruleError(core.RuleDocsDrift, attr.Pos,
+				"documented attribute `%s` is missing in the schema of `%s`", docKey(attr), g.Resource))
+			continue
+		}
+		if fld == nil {
+			continue
+		}
+		mErr = multierror.Append(mErr, g.checkDocLabels(attr, fld))
+	}
+	mErr = multierror.Append(mErr, g.checkUndocumented(g.Schema, "", documented, blocks, doc.Path))
+	return mErr.ErrorOrNil()
+}
+
+// checkUndocumented checks that all attributes of the schema are documented,
+// nested blocks are checked only if the page has a section for the block
+func (g Generator) checkUndocumented(schema map[string]*Field, block string, documented map[string]bool,
+	blocks map[string]map[string]bool, docPath string) error {
+	keys := make([]string, 0, len(schema))
+	for key := range schema {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	mErr := &multierror.Error{}
+	for _, key := range keys {
+		fld := schema[key]
+		if fld == nil {
+			continue
+		}
+		if !documented[key] {
+			mErr = multierror.Append(mErr, g.ruleError(core.RuleDocsDrift, fld.KeyPos,
+				"attribute `%s` is not documented in %s", docKey(core.DocAttribute{Block: block, Key: key}), docPath))
+		}
+		if nested, ok := blocks[key]; ok && fld.Schema != nil {
+			mErr = multierror.Append(mErr, g.checkUndocumented(fld.Schema, key, nested, blocks, docPath))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+func docKey(attr core.DocAttribute) string {
+	if attr.Block == "" {
+		return attr.Key
+	}
+	return attr.Block + "." + attr.Key
+}
+
+func (g Generator) checkDocLabels(attr core.DocAttribute, fld *Field) error {
+	var actual string
+	switch {
+	case fld.Required:
+		actual = "required"
+	case fld.Optional:
+		actual = "optional"
+	default:
+		actual = "computed-only"
+	}
+	switch {
+	case attr.Required && !fld.Required:
+		return g.ruleError(core.RuleDocsDrift, attr.Pos,
+			"attribute `%s` is documented as required, but it's %s in the schema", docKey(attr), actual)
+	case attr.Optional && !fld.Optional:
+		return g.ruleError(core.RuleDocsDrift, attr.Pos,
+			"attribute `%s` is documented as optional, but it's %s in the schema", docKey(attr), actual)
+	}
+	return nil
+}
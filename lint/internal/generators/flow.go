@@ -0,0 +1,214 @@
+package generators
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+)
+
+// valueFlow collects keys of nested block values built in the function body: map literals, index writes,
+// `append` calls and writes to the elements through range variables, writes after the site are ignored
+type valueFlow struct {
+	g     Generator
+	scope *core.Scope
+	body  *ast.BlockStmt
+	site  token.Pos
+	seen  map[*ast.Object]bool
+	// open is set if some keys can't be resolved statically, e.g. the map is filled in a loop
+	open bool
+}
+
+func newValueFlow(g Generator, scope *core.Scope, body *ast.BlockStmt, site token.Pos) *valueFlow {
+	return &valueFlow{g: g, scope: scope, body: body, site: site, seen: map[*ast.Object]bool{}}
+}
+
+// keys returns keys the map value or the elements of the slice value can contain
+func (f *valueFlow) keys(expr ast.Expr) []core.HelperKey {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return f.keys(e.X)
+	case *ast.CompositeLit:
+		return f.literalKeys(e)
+	case *ast.Ident:
+		return f.variableKeys(e)
+	case *ast.CallExpr:
+		return f.callKeys(e)
+	}
+	f.open = true
+	return nil
+}
+
+// literalKeys returns keys of the map literal or of the elements of the slice literal
+func (f *valueFlow) literalKeys(lit *ast.CompositeLit) []core.HelperKey {
+	if _, ok := lit.Type.(*ast.ArrayType); ok {
+		var result []core.HelperKey
+		for _, elt := range lit.Elts {
+			result = append(result, f.keys(elt)...)
+		}
+		return result
+	}
+	if lit.Type != nil && !isInterfaceMap(lit.Type) {
+		f.open = true // e.g. struct value
+		return nil
+	}
+	var result []core.HelperKey
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := stringKey(kv.Key)
+		if !ok {
+			f.open = true
+			continue
+		}
+		result = append(result, core.HelperKey{Key: key, Pos: kv.Key.Pos(), Type: f.g.helperValueType(kv.Value, f.scope)})
+	}
+	return result
+}
+
+// callKeys handles `make`, `append` and helper calls
+func (f *valueFlow) callKeys(call *ast.CallExpr) []core.HelperKey {
+	if ident, ok := call.Fun.(*ast.Ident); ok && ident.Obj == nil {
+		switch ident.Name {
+		case "make":
+			return nil
+		case "append":
+			var result []core.HelperKey
+			for _, arg := range call.Args {
+				result = append(result, f.keys(arg)...)
+			}
+			return result
+		}
+	}
+	if summary := f.g.helperSummary(call); summary != nil {
+		f.open = f.open || summary.Open
+		return summary.Produced
+	}
+	f.open = true
+	return nil
+}
+
+// rangeSource returns the variable ranged over if the identifier is the value of the range statement
+func rangeSource(ident *ast.Ident) *ast.Object {
+	assign, ok := ident.Obj.Decl.(*ast.AssignStmt)
+	if !ok || len(assign.Lhs) != 2 || len(assign.Rhs) != 1 {
+		return nil
+	}
+	value, ok := assign.Lhs[1].(*ast.Ident)
+	if !ok || value.Obj != ident.Obj {
+		return nil
+	}
+	rng, ok := assign.Rhs[0].(*ast.UnaryExpr)
+	if !ok || rng.Op != token.RANGE {
+		return nil
+	}
+	src, ok := rng.X.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	return src.Obj
+}
+
+// variableKeys returns keys from the declaration of the variable and writes to it before the site
+func (f *valueFlow) variableKeys(ident *ast.Ident) []core.HelperKey {
+	obj := ident.Obj
+	if obj == nil {
+		f.open = true
+		return nil
+	}
+	if f.seen[obj] {
+		return nil
+	}
+	f.seen[obj] = true
+
+	var result []core.HelperKey
+	switch decl := obj.Decl.(type) {
+	case *ast.ValueSpec:
+		for _, value := range assignedValues(ident) {
+			result = append(result, f.keys(value)...)
+		}
+		if decl.Type != nil && len(decl.Values) == 0 && !isInterfaceMap(decl.Type) {
+			if _, ok := decl.Type.(*ast.ArrayType); !ok {
+				f.open = true
+			}
+		}
+	case *ast.AssignStmt:
+		if src := rangeSource(ident); src != nil {
+			f.open = true // elements of other collections are not tracked
+			return nil
+		}
+		for _, value := range assignedValues(ident) {
+			result = append(result, f.keys(value)...)
+		}
+	default:
+		f.open = true // e.g. function parameter
+		return nil
+	}
+
+	ast.Inspect(f.body, func(node ast.Node) bool {
+		assign, ok := node.(*ast.AssignStmt)
+		if !ok || assign.Pos() >= f.site || assign.Pos() == obj.Decl.(ast.Node).Pos() {
+			return true
+		}
+		for i, lhs := range assign.Lhs {
+			var rhs ast.Expr
+			if len(assign.Lhs) == len(assign.Rhs) {
+				rhs = assign.Rhs[i]
+			}
+			result = append(result, f.writeKeys(obj, lhs, rhs)...)
+		}
+		return true
+	})
+	return result
+}
+
+// writeKeys returns keys added to the variable by the assignment
+func (f *valueFlow) writeKeys(obj *ast.Object, lhs, rhs ast.Expr) []core.HelperKey {
+	switch l := lhs.(type) {
+	case *ast.Ident:
+		if l.Obj != obj || rhs == nil {
+			return nil
+		}
+		return f.keys(rhs) // reassignment or `s = append(s, ...)`
+	case *ast.IndexExpr:
+		x, ok := l.X.(*ast.Ident)
+		if !ok || x.Obj == nil {
+			return nil
+		}
+		if x.Obj != obj && rangeSource(x) != obj {
+			return nil
+		}
+		key, ok := stringKey(l.Index)
+		if !ok {
+			if x.Obj != obj || rhs == nil {
+				f.open = true
+				return nil
+			}
+			return f.keys(rhs) // slice element
+		}
+		hk := core.HelperKey{Key: key, Pos: l.Index.Pos()}
+		if rhs != nil {
+			hk.Type = f.g.helperValueType(rhs, f.scope)
+		}
+		return []core.HelperKey{hk}
+	}
+	return nil
+}
+
+// uniqueKeys removes keys found several times by different paths
+func uniqueKeys(keys []core.HelperKey) []core.HelperKey {
+	seen := map[token.Pos]bool{}
+	result := make([]core.HelperKey, 0, len(keys))
+	for _, hk := range keys {
+		if seen[hk.Pos] {
+			continue
+		}
+		seen[hk.Pos] = true
+		result = append(result, hk)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Pos < result[j].Pos })
+	return result
+}
@@ -15,7 +15,34 @@ import (
 )
 
 type Field struct {
-	Type string
+	Type      string
+	Pos       token.Pos
+	Required  bool
+	Optional  bool
+	Computed  bool
+	ForceNew  bool
+	Sensitive bool
+	MaxItems  int
+	// Deprecated is the deprecation message, `deprecated` is used if it can't be resolved statically
+	Deprecated string
+	// Description is the field description, empty if it can't be resolved statically
+	Description string
+	// KeyPos is position of the field key in the schema
+	KeyPos token.Pos
+	// Helper is full name of the function returning the field declaration, e.g. `common.TagsSchema`
+	Helper string
+	// Default is expression used as a field `Default` value
+	Default ast.Expr
+	// Validators are expressions used as `ValidateFunc` or `ValidateDiagFunc`
+	Validators []ast.Expr
+	// Attrs contains positions of all attributes set in the field declaration
+	Attrs map[string]token.Pos
+	// Relations contains key lists of the field, e.g. `ConflictsWith`
+	Relations map[string][]core.Reference
+	// Elem is set for collections of primitives
+	Elem *Field
+	// Schema is set for collections of nested resources
+	Schema map[string]*Field
 }
 
 type StructFields map[string]core.Type
@@ -24,18 +51,43 @@ type StructFields map[string]core.Type
 type Generator struct {
 	FSet         *token.FileSet
 	Pkg          *packages.Package
+	Config       *core.Config
 	Name         string
+	Pos          token.Pos
 	Schema       map[string]*Field
 	OperatingFns []*ast.FuncDecl
+	// Operations are CRUD functions of the resource, keyed by operation name, e.g. `Update`
+	Operations map[string]*ast.FuncDecl
+	// Resource is the resource registration in the provider, empty if the provider map is not found
+	Resource core.ResourceInfo
+	Project  *core.Project
+	// SDK is the schema package used by the generator and its SDK generation
+	SDK core.SchemaImport
+	// SchemaVersion is the version of the resource state, -1 if it can't be resolved
+	SchemaVersion  int
+	StateUpgraders []StateUpgrader
 
 	scopeCache map[string]*core.Scope // scopes of any imported library, populated lazily
 }
 
-func NewGenerator(name string, fset *token.FileSet, pkg *packages.Package, sharedScopes map[string]*core.Scope) (*Generator, error) {
+// StateUpgrader is a single `StateUpgraders` entry of the resource
+type StateUpgrader struct {
+	// Version is the state version being upgraded, -1 if it can't be resolved
+	Version int
+	Pos     token.Pos
+	// Upgrade is the upgrade function, nil if it's not declared in the resource package
+	Upgrade *ast.FuncDecl
+}
+
+func NewGenerator(name string, fset *token.FileSet, pkg *packages.Package, config *core.Config, project *core.Project, sharedScopes map[string]*core.Scope) (*Generator, error) {
 	gen := &Generator{
 		FSet:       fset,
 		Pkg:        pkg,
+		Config:     config,
 		Name:       name,
+		Operations: map[string]*ast.FuncDecl{},
+		Resource:   project.Resources[core.MethodName(pkg.ID, name)],
+		Project:    project,
 		scopeCache: sharedScopes,
 	}
 	_, ok := sharedScopes[pkg.ID] // should be populated in parser
@@ -69,21 +121,6 @@ func getDName(fn *ast.FuncDecl) string {
 	return dataFld.Names[0].Name
 }
 
-func isDSetSelector(expr ast.Expr, dName string) bool {
-	sel, ok := expr.(*ast.SelectorExpr)
-	if !ok {
-		return false
-	}
-	idnt, ok := sel.X.(*ast.Ident)
-	if !ok {
-		return false
-	}
-	if idnt.Name != dName || sel.Sel.Name != "Set" {
-		return false
-	}
-	return true
-}
-
 // simplifyPath - simplify absolute path if possible
 func simplifyPath(src string) string {
 	cwd, err := os.Getwd()
@@ -100,6 +137,28 @@ func simplifyPath(src string) string {
 	return res
 }
 
+// configName returns the name the resource is referred by in the config,
+// the type name of the registered resource or the generator name otherwise
+func (g Generator) configName() string {
+	if g.Resource.TypeName != "" {
+		return g.Resource.TypeName
+	}
+	return g.Name
+}
+
+// position returns position for error messages
+func (g Generator) position(p token.Pos) token.Position {
+	return Position(g.FSet, p)
+}
+
+// Position returns the position used in error messages, with the path relative to the working directory
+func Position(fSet *token.FileSet, p token.Pos) token.Position {
+	pos := fSet.Position(p)
+	pos.Column = 0 // no need for such details
+	pos.Filename = simplifyPath(pos.Filename)
+	return pos
+}
+
 func (g Generator) getKey(key string) (*Field, error) {
 	fld, ok := g.Schema[key]
 	if !ok {
@@ -116,7 +175,11 @@ func (g Generator) ValidateSetters() error {
 		// seconds - go through function body finding `d.Set` calls
 		ast.Inspect(fn.Body, func(node ast.Node) bool {
 			if call, ok := node.(*ast.CallExpr); ok {
-				if !isDSetSelector(call.Fun, dName) {
+				if g.features().PartialSetter && isDataMethod(call.Fun, dName, "SetPartial") && len(call.Args) == 1 {
+					mErr = multierror.Append(mErr, g.validatePartialSetter(call))
+					return false
+				}
+				if !isDataMethod(call.Fun, dName, "Set") {
 					return true // go on
 				}
 				// d.Set always has two arguments
@@ -141,10 +204,7 @@ func (g Generator) validateSetter(call *ast.CallExpr) error {
 	}
 	key := strings.Trim(keyExpr.Value, `"`)
 
-	// position for error messages
-	pos := g.FSet.Position(call.Pos())
-	pos.Column = 0 // no need for such details
-	pos.Filename = simplifyPath(pos.Filename)
+	pos := g.position(call.Pos())
 
 	fld, err := g.getKey(key)
 	if err != nil {
@@ -171,6 +231,19 @@ func (g Generator) validateSetter(call *ast.CallExpr) error {
 	return nil
 }
 
+// validatePartialSetter checks the key of SDK v1 `d.SetPartial` call
+func (g Generator) validatePartialSetter(call *ast.CallExpr) error {
+	keyExpr, ok := call.Args[0].(*ast.BasicLit)
+	if !ok {
+		return nil
+	}
+	key := strings.Trim(keyExpr.Value, `"`)
+	if _, err := g.getKey(key); err != nil {
+		return fmt.Errorf("%s - broken partial setter for field `%s`: %w", g.position(call.Pos()).String(), key, err)
+	}
+	return nil
+}
+
 func (g Generator) extendedMatch(typ core.Type, expected string) bool {
 	base := typ.Matches(expected)
 	if base {
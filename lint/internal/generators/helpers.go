@@ -0,0 +1,426 @@
+package generators
+
+import (
+	"go/ast"
+	"go/token"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+)
+
+// isHelperName checks if the function is a `flatten*` or `expand*` helper, e.g. `flattenRules` or `ExpandTags`
+func isHelperName(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.HasPrefix(lower, "flatten") || strings.HasPrefix(lower, "expand")
+}
+
+// stringKey returns value of the string literal used as a map key
+func stringKey(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	key, err := strconv.Unquote(lit.Value)
+	return key, err == nil
+}
+
+// resolveHelper finds the scope and the declaration of the called helper
+func (g Generator) resolveHelper(call *ast.CallExpr) (*core.Scope, *ast.FuncDecl) {
+	var (
+		pkg  = g.Pkg
+		name string
+	)
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		name = fun.Name
+	case *ast.SelectorExpr:
+		x, ok := fun.X.(*ast.Ident)
+		if !ok || x.Obj != nil {
+			return nil, nil
+		}
+		dep, err := importByName(pkg, g.absoluteImport(x.Name, fun, pkg))
+		if err != nil {
+			return nil, nil
+		}
+		pkg, name = dep, fun.Sel.Name
+	default:
+		return nil, nil
+	}
+	if !isHelperName(name) {
+		return nil, nil
+	}
+	scope, err := g.getCachedScope(pkg)
+	if err != nil {
+		return nil, nil
+	}
+	decl, ok := scope.FuncDecls[name]
+	if !ok || decl.Body == nil {
+		return nil, nil
+	}
+	return scope, decl
+}
+
+// helperSummary returns cached summary of the called helper, nil if the call is not a helper call
+func (g Generator) helperSummary(call *ast.CallExpr) *core.HelperSummary {
+	scope, decl := g.resolveHelper(call)
+	if decl == nil {
+		return nil
+	}
+	if summary, ok := scope.Helpers[decl.Name.Name]; ok {
+		return summary
+	}
+	pkgPath := strings.Fields(scope.Package.ID)[0] // test variants have IDs like `<path> [<path>.test]`
+	summary := &core.HelperSummary{Name: path.Base(pkgPath) + "." + decl.Name.Name}
+	scope.Helpers[decl.Name.Name] = summary // recursive helpers see the empty summary
+	summary.Produced, summary.Open = g.producedKeys(decl, scope)
+	summary.Read = readKeys(decl)
+	return summary
+}
+
+// isInterfaceMap checks if the type expression is `map[string]interface{}`
+func isInterfaceMap(expr ast.Expr) bool {
+	return expr != nil && typeString(expr) == "map[string]interface{}"
+}
+
+// producedKeys collects keys of the maps returned by the helper, following writes to the local maps and slices
+func (g Generator) producedKeys(decl *ast.FuncDecl, scope *core.Scope) ([]core.HelperKey, bool) {
+	var (
+		result []core.HelperKey
+		open   bool
+	)
+	ast.Inspect(decl.Body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if len(n.Results) == 0 {
+				return true
+			}
+			flow := newValueFlow(g, scope, decl.Body, n.Pos())
+			result = append(result, flow.keys(n.Results[0])...)
+			open = open || flow.open
+		}
+		return true
+	})
+	return uniqueKeys(result), open
+}
+
+// unresolved checks if the type contains stubs of expressions which types can't be resolved
+func unresolved(typ core.Type) bool {
+	switch t := typ.(type) {
+	case *core.StubType:
+		return true
+	case *core.WrapperType:
+		return unresolved(t.Wrapped)
+	case *core.ArrayType:
+		return unresolved(t.ItemType)
+	case *core.FuncType:
+		return len(t.Results) == 0 || unresolved(t.Results[0])
+	}
+	return false
+}
+
+// helperValueType returns type of the produced value, nil if it can't be resolved
+func (g Generator) helperValueType(expr ast.Expr, scope *core.Scope) core.Type {
+	typ, err := g.getExpType(expr, scope.Package)
+	if err != nil || typ == nil || unresolved(typ) {
+		return nil
+	}
+	return typ
+}
+
+// derivesFrom checks if the variable is the parameter or is assigned from it,
+// values read with string keys belong to deeper blocks and are not followed
+func derivesFrom(ident *ast.Ident, param *ast.Object, depth int) bool {
+	if ident.Obj == nil || depth > maxValueDepth {
+		return false
+	}
+	if ident.Obj == param {
+		return true
+	}
+	found := false
+	for _, value := range assignedValues(ident) {
+		ast.Inspect(value, func(node ast.Node) bool {
+			switch n := node.(type) {
+			case *ast.IndexExpr:
+				if _, ok := stringKey(n.Index); ok {
+					return false
+				}
+			case *ast.Ident:
+				found = found || derivesFrom(n, param, depth+1)
+			}
+			return !found
+		})
+	}
+	return found
+}
+
+// readKeys collects keys read from the maps of the first helper argument
+func readKeys(decl *ast.FuncDecl) []core.HelperKey {
+	params := decl.Type.Params
+	if params.NumFields() == 0 || len(params.List[0].Names) == 0 {
+		return nil
+	}
+	param := params.List[0].Names[0].Obj
+	var result []core.HelperKey
+	asserted := map[*ast.IndexExpr]bool{}
+	readKey := func(expr ast.Expr) (*ast.IndexExpr, string, bool) {
+		index, ok := expr.(*ast.IndexExpr)
+		if !ok {
+			return nil, "", false
+		}
+		x, ok := index.X.(*ast.Ident)
+		if !ok || !derivesFrom(x, param, 0) {
+			return nil, "", false
+		}
+		key, ok := stringKey(index.Index)
+		return index, key, ok
+	}
+	ast.Inspect(decl.Body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.TypeAssertExpr:
+			if n.Type == nil {
+				return true
+			}
+			if index, key, ok := readKey(n.X); ok {
+				asserted[index] = true
+				result = append(result, core.HelperKey{Key: key, Pos: index.Index.Pos(), Asserted: typeString(n.Type)})
+			}
+		case *ast.IndexExpr:
+			if asserted[n] {
+				return true
+			}
+			if _, key, ok := readKey(n); ok {
+				result = append(result, core.HelperKey{Key: key, Pos: n.Index.Pos()})
+			}
+		}
+		return true
+	})
+	return result
+}
+
+// helperTypeMatches checks the produced value type, types which can't be resolved match any schema type
+func (g Generator) helperTypeMatches(typ core.Type, expected string) bool {
+	if typ == nil || typ.Matches(expected) {
+		return true
+	}
+	pkgID := typ.Package()
+	if pkgID == "" {
+		return false // builtin type
+	}
+	scope, ok := g.scopeCache[pkgID]
+	if !ok {
+		return true
+	}
+	internal, err := g.resolveLocalType(typ.Name(), scope.Package)
+	if err != nil || internal == nil {
+		return true
+	}
+	return internal.Matches(expected)
+}
+
+// helperArgKey returns the key of the `d.Get` value passed to the helper, e.g. `d.Get("rule").(*schema.Set).List()`
+func helperArgKey(expr ast.Expr, dName string) string {
+	switch e := expr.(type) {
+	case *ast.CallExpr:
+		if sel, ok := e.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "List" && len(e.Args) == 0 {
+			return helperArgKey(sel.X, dName)
+		}
+		return dataKey(e, dName, 0)
+	case *ast.TypeAssertExpr:
+		return helperArgKey(e.X, dName)
+	case *ast.ParenExpr:
+		return helperArgKey(e.X, dName)
+	case *ast.Ident:
+		return dataKey(e, dName, 0)
+	}
+	return ""
+}
+
+// ValidateHelperKeys checks summaries of `flatten*` helpers used in `d.Set` and `expand*` helpers
+// called with `d.Get` values against the nested schema of the attribute
+func (g Generator) ValidateHelperKeys() error {
+	mErr := &multierror.Error{}
+	checked := map[string]bool{}
+	for _, fn := range g.OperatingFns {
+		dName := getDName(fn)
+		if dName == "" {
+			continue
+		}
+		ast.Inspect(fn.Body, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			if isDataMethod(call.Fun, dName, "Set") && len(call.Args) == 2 {
+				key, ok := stringKey(call.Args[0])
+				if !ok {
+					return true
+				}
+				value := call.Args[1]
+				if ident, ok := value.(*ast.Ident); ok && ident.Obj != nil {
+					if values := assignedValues(ident); len(values) == 1 {
+						value = values[0]
+					}
+				}
+				if helperCall, ok := value.(*ast.CallExpr); ok {
+					if summary := g.helperSummary(helperCall); summary != nil {
+						if id := "produced:" + summary.Name + ":" + key; len(summary.Produced) != 0 && !checked[id] {
+							checked[id] = true
+							mErr = multierror.Append(mErr, g.checkProduced(summary, key, call.Pos()))
+						}
+						return true
+					}
+				}
+				mErr = multierror.Append(mErr, g.checkSetValue(fn.Body, call, key))
+				return true
+			}
+			if len(call.Args) == 0 {
+				return true
+			}
+			key := helperArgKey(call.Args[0], dName)
+			if key == "" {
+				return true
+			}
+			if summary := g.helperSummary(call); summary != nil && len(summary.Read) != 0 {
+				if id := "read:" + summary.Name + ":" + key; !checked[id] {
+					checked[id] = true
+					mErr = multierror.Append(mErr, g.checkRead(summary, key, call.Pos()))
+				}
+			}
+			return true
+		})
+	}
+	return mErr.ErrorOrNil()
+}
+
+// blockSchema returns nested schema of the attribute, nil for attributes which are not blocks
+func (g Generator) blockSchema(key string) map[string]*Field {
+	fld := g.schemaField(key)
+	if fld == nil {
+		return nil
+	}
+	return fld.Schema
+}
+
+func (g Generator) checkProduced(summary *core.HelperSummary, key string, callPos token.Pos) error {
+	schema := g.blockSchema(key)
+	if schema == nil {
+		return nil
+	}
+	mErr := &multierror.Error{}
+	produced := map[string]bool{}
+	for _, hk := range summary.Produced {
+		produced[hk.Key] = true
+		fld, ok := schema[hk.Key]
+		if !ok {
+			mErr = multierror.Append(mErr, g.ruleError(core.RuleHelperKeys, hk.Pos,
+				"`%s` produces key `%s` missing in the schema of `%s`", summary.Name, hk.Key, key))
+			continue
+		}
+		if fld == nil {
+			continue
+		}
+		if expected := typeMapping[fld.Type]; expected != "" && !g.helperTypeMatches(hk.Type, expected) {
+			mErr = multierror.Append(mErr, g.ruleError(core.RuleHelperKeys, hk.Pos,
+				"`%s` produces `%s.%s` of type `%s`, expected `%s`", summary.Name, key, hk.Key, hk.Type, expected))
+		}
+	}
+	if summary.Open {
+		return mErr.ErrorOrNil()
+	}
+	for _, name := range sortedKeys(schema) {
+		if schema[name] != nil && !produced[name] {
+			mErr = multierror.Append(mErr, g.ruleError(core.RuleHelperKeys, callPos,
+				"`%s` doesn't produce `%s.%s`", summary.Name, key, name))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// checkSetValue checks keys of the local maps and slices of maps set with `d.Set`,
+// all writes to the value in the function before the call are taken into account
+func (g Generator) checkSetValue(body *ast.BlockStmt, call *ast.CallExpr, key string) error {
+	switch call.Args[1].(type) {
+	case *ast.Ident, *ast.CompositeLit:
+	default:
+		return nil
+	}
+	schema := g.blockSchema(key)
+	if schema == nil {
+		return nil
+	}
+	scope, err := g.getCachedScope(g.Pkg)
+	if err != nil {
+		return nil
+	}
+	flow := newValueFlow(g, scope, body, call.Pos())
+	keys := uniqueKeys(flow.keys(call.Args[1]))
+	if len(keys) == 0 {
+		return nil
+	}
+	mErr := &multierror.Error{}
+	set := map[string]bool{}
+	for _, hk := range keys {
+		set[hk.Key] = true
+		fld, ok := schema[hk.Key]
+		if !ok {
+			mErr = multierror.Append(mErr, g.ruleError(core.RuleHelperKeys, hk.Pos,
+				"key `%s` set to `%s` is missing in the schema", hk.Key, key))
+			continue
+		}
+		if fld == nil {
+			continue
+		}
+		if expected := typeMapping[fld.Type]; expected != "" && !g.helperTypeMatches(hk.Type, expected) {
+			mErr = multierror.Append(mErr, g.ruleError(core.RuleHelperKeys, hk.Pos,
+				"`%s.%s` is set to value of type `%s`, expected `%s`", key, hk.Key, hk.Type, expected))
+		}
+	}
+	if flow.open {
+		return mErr.ErrorOrNil()
+	}
+	for _, name := range sortedKeys(schema) {
+		if schema[name] != nil && !set[name] {
+			mErr = multierror.Append(mErr, g.ruleError(core.RuleHelperKeys, call.Pos(),
+				"value set to `%s` doesn't contain `%s.%s`", key, key, name))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+func (g Generator) checkRead(summary *core.HelperSummary, key string, callPos token.Pos) error {
+	schema := g.blockSchema(key)
+	if schema == nil {
+		return nil
+	}
+	mErr := &multierror.Error{}
+	read := map[string]bool{}
+	for _, hk := range summary.Read {
+		read[hk.Key] = true
+		fld, ok := schema[hk.Key]
+		if !ok {
+			mErr = multierror.Append(mErr, g.ruleError(core.RuleHelperKeys, hk.Pos,
+				"`%s` reads key `%s` missing in the schema of `%s`", summary.Name, hk.Key, key))
+			continue
+		}
+		if fld == nil || hk.Asserted == "" {
+			continue
+		}
+		if expected, ok := schemaAssertions[fld.Type]; ok && hk.Asserted != expected {
+			mErr = multierror.Append(mErr, g.ruleError(core.RuleHelperKeys, hk.Pos,
+				"`%s` asserts `%s.%s` to `%s`, but it's `%s`", summary.Name, key, hk.Key, hk.Asserted, fld.Type))
+		}
+	}
+	for _, name := range sortedKeys(schema) {
+		fld := schema[name]
+		if fld != nil && (fld.Required || fld.Optional) && !read[name] {
+			mErr = multierror.Append(mErr, g.ruleError(core.RuleHelperKeys, callPos,
+				"`%s` doesn't read `%s.%s` argument", summary.Name, key, name))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
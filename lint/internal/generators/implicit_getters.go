@@ -0,0 +1,195 @@
+package generators
+
+import (
+	"go/ast"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+	"golang.org/x/tools/go/packages"
+)
+
+// implicitRead is a key read by the implicit getter
+type implicitRead struct {
+	Getter string
+	Key    string
+	Call   *ast.CallExpr
+	// Expected is the expected key declaration, nil if only the key presence is checked
+	Expected *core.CanonicalField
+}
+
+// ValidateImplicitGetters checks that keys read by configured functions called with `*schema.ResourceData`
+// of the resource operations, e.g. `config.GetRegion(d)`, are declared in the schema
+func (g Generator) ValidateImplicitGetters() error {
+	var fns []dataFunc
+	visited := map[*ast.FuncDecl]bool{}
+	for _, name := range sortedOperations(g.Operations) {
+		for _, fn := range g.reachableFns(g.Operations[name]) {
+			if !visited[fn.Decl] {
+				visited[fn.Decl] = true
+				fns = append(fns, fn)
+			}
+		}
+	}
+
+	reported := map[string]bool{}
+	mErr := &multierror.Error{}
+	for _, read := range g.implicitReads(fns) {
+		id := read.Getter + " " + read.Key
+		if reported[id] {
+			continue
+		}
+		reported[id] = true
+		getter := shortFuncName(read.Getter)
+		fld, ok := g.Schema[read.Key]
+		if !ok {
+			mErr = multierror.Append(mErr, g.ruleError(core.RuleImplicitGetters, read.Call.Pos(),
+				"`%s` reads `%s`, but it is missing in the schema defined in `%s`", getter, read.Key, g.Name))
+			continue
+		}
+		if fld == nil || read.Expected == nil {
+			continue
+		}
+		if mismatches := canonicalMismatches(fld, *read.Expected); len(mismatches) != 0 {
+			mErr = multierror.Append(mErr, g.ruleError(core.RuleImplicitGetters, read.Call.Pos(),
+				"`%s` reads `%s` declared as `%s`, expected %s",
+				getter, read.Key, fld.signature(), strings.Join(mismatches, ", ")))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// implicitReads finds calls of the configured getters with `*schema.ResourceData` argument
+func (g Generator) implicitReads(fns []dataFunc) []implicitRead {
+	var result []implicitRead
+	for _, fn := range fns {
+		ast.Inspect(fn.Decl.Body, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok || !passesData(call, fn.DName) {
+				return true
+			}
+			name := g.calleeName(call, fn.Pkg)
+			getter, ok := g.Config.ImplicitGetter(name)
+			if !ok {
+				return true
+			}
+			keys := make([]string, 0, len(getter.Keys))
+			for key := range getter.Keys {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				expected := getter.Keys[key]
+				result = append(result, implicitRead{Getter: name, Key: key, Call: call, Expected: &expected})
+			}
+			if idx := getter.KeyArgument; idx != nil && *idx < len(call.Args) {
+				if lit, ok := call.Args[*idx].(*ast.BasicLit); ok {
+					key, _ := core.UnwrapString(lit)
+					result = append(result, implicitRead{Getter: name, Key: key, Call: call})
+				}
+			}
+			return true
+		})
+	}
+	return result
+}
+
+// passesData checks if `*schema.ResourceData` is one of the call arguments
+func passesData(call *ast.CallExpr, dName string) bool {
+	for _, arg := range call.Args {
+		if ident, ok := arg.(*ast.Ident); ok && ident.Name == dName {
+			return true
+		}
+	}
+	return false
+}
+
+// calleeName returns `<import path>.<function>` of the called function or `<import path>.<type>.<method>`
+// of the method called on a local variable, empty string if it can't be resolved
+func (g Generator) calleeName(call *ast.CallExpr, pkg *packages.Package) string {
+	switch fn := call.Fun.(type) {
+	case *ast.Ident:
+		return core.MethodName(pkg.ID, fn.Name)
+	case *ast.SelectorExpr:
+		x, ok := fn.X.(*ast.Ident)
+		if !ok {
+			return ""
+		}
+		if x.Obj == nil {
+			path := g.absoluteImport(x.Name, fn, pkg)
+			if path == "" {
+				return ""
+			}
+			return core.MethodName(path, fn.Sel.Name)
+		}
+		typ := g.varTypeName(x, pkg)
+		if typ == "" {
+			return ""
+		}
+		return core.MethodName(typ, fn.Sel.Name)
+	}
+	return ""
+}
+
+// varTypeName returns `<import path>.<type>` of the variable declared with explicit type,
+// type assertion or composite literal, e.g. `config := meta.(*cfg.Config)`
+func (g Generator) varTypeName(ident *ast.Ident, pkg *packages.Package) string {
+	if ident.Obj == nil {
+		return ""
+	}
+	var typ ast.Expr
+	switch decl := ident.Obj.Decl.(type) {
+	case *ast.Field:
+		typ = decl.Type
+	case *ast.ValueSpec:
+		typ = decl.Type
+		if typ == nil {
+			for i, name := range decl.Names {
+				if name.Obj == ident.Obj && i < len(decl.Values) {
+					typ = valueTypeExpr(decl.Values[i])
+				}
+			}
+		}
+	case *ast.AssignStmt:
+		if len(decl.Lhs) != len(decl.Rhs) {
+			return ""
+		}
+		for i, lhs := range decl.Lhs {
+			if l, ok := lhs.(*ast.Ident); ok && l.Obj == ident.Obj {
+				typ = valueTypeExpr(decl.Rhs[i])
+			}
+		}
+	}
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	switch t := typ.(type) {
+	case *ast.Ident:
+		return core.MethodName(pkg.ID, t.Name)
+	case *ast.SelectorExpr:
+		x, ok := t.X.(*ast.Ident)
+		if !ok {
+			return ""
+		}
+		path := g.absoluteImport(x.Name, t, pkg)
+		if path == "" {
+			return ""
+		}
+		return core.MethodName(path, t.Sel.Name)
+	}
+	return ""
+}
+
+// valueTypeExpr returns the type expression of type assertion or composite literal
+func valueTypeExpr(expr ast.Expr) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.TypeAssertExpr:
+		return e.Type
+	case *ast.UnaryExpr:
+		return valueTypeExpr(e.X)
+	case *ast.CompositeLit:
+		return e.Type
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package generators
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+	"golang.org/x/tools/go/packages"
+)
+
+// Source is a response struct field used in the attribute value
+type Source struct {
+	// Struct is the full name of the struct declaring the field, e.g. `<import path>.Server`,
+	// variable name is used for anonymous structs
+	Struct string
+	Field  string
+	Pos    token.Pos
+}
+
+// Lineage contains response struct fields feeding the resource attributes
+type Lineage struct {
+	// Sources are fields used in `d.Set` values by top-level attribute key
+	Sources map[string][]Source
+	// Fields are exported fields of all structs used as sources by the full struct name
+	Fields map[string][]string
+}
+
+// structDecl is the struct declaration with the package it's declared in
+type structDecl struct {
+	name string
+	st   *ast.StructType
+	pkg  *packages.Package
+}
+
+// namedStruct finds declaration of the named struct type
+func (g Generator) namedStruct(typ core.Type, pkg *packages.Package) *structDecl {
+	if typ == nil {
+		return nil
+	}
+	if _, ok := typ.(*core.ArrayType); ok {
+		return nil
+	}
+	if typ.Package() != "" {
+		dep, err := importByName(pkg, typ.Package())
+		if err != nil {
+			return nil
+		}
+		pkg = dep
+	}
+	scope, err := g.getCachedScope(pkg)
+	if err != nil {
+		return nil
+	}
+	decl, ok := scope.StructDecls[typ.Name()]
+	if !ok {
+		return nil
+	}
+	st, ok := decl.Specs[0].(*ast.TypeSpec).Type.(*ast.StructType)
+	if !ok {
+		return nil
+	}
+	return &structDecl{name: core.MethodName(pkg.ID, typ.Name()), st: st, pkg: pkg}
+}
+
+// valueStruct finds struct declaration of the selector `X` expression
+func (g Generator) valueStruct(expr ast.Expr, pkg *packages.Package) *structDecl {
+	if ident, ok := expr.(*ast.Ident); ok && ident.Obj != nil {
+		if spec, ok := ident.Obj.Decl.(*ast.ValueSpec); ok {
+			if st, ok := spec.Type.(*ast.StructType); ok {
+				return &structDecl{name: ident.Name, st: st, pkg: pkg}
+			}
+		}
+	}
+	typ, err := g.getExpType(expr, pkg)
+	if err != nil {
+		return nil
+	}
+	return g.namedStruct(typ, pkg)
+}
+
+// fieldOwner finds the struct declaring the field, fields promoted from embedded structs are resolved
+// to the embedded struct declarations
+func (g Generator) fieldOwner(decl *structDecl, field string, depth int) *structDecl {
+	if decl == nil || depth > maxValueDepth {
+		return nil
+	}
+	var embedded []ast.Expr
+	for _, fld := range decl.st.Fields.List {
+		if len(fld.Names) == 0 {
+			embedded = append(embedded, fld.Type)
+			continue
+		}
+		for _, name := range fld.Names {
+			if name.Name == field {
+				return decl
+			}
+		}
+	}
+	for _, expr := range embedded {
+		typ, err := g.getExpType(expr, decl.pkg)
+		if err != nil {
+			continue
+		}
+		if owner := g.fieldOwner(g.namedStruct(typ, decl.pkg), field, depth+1); owner != nil {
+			return owner
+		}
+	}
+	return nil
+}
+
+// exportedFields returns exported fields declared in the struct
+func exportedFields(st *ast.StructType) []string {
+	var result []string
+	for _, fld := range st.Fields.List {
+		for _, name := range fld.Names {
+			if name.IsExported() {
+				result = append(result, name.Name)
+			}
+		}
+	}
+	return result
+}
+
+// maxValueDepth limits tracing of local variables and embedded structs
+const maxValueDepth = 5
+
+// valueSources collects struct fields used in the expression, local variables are traced to their assignments
+func (g Generator) valueSources(expr ast.Expr, lineage *Lineage, depth int) []Source {
+	if depth > maxValueDepth {
+		return nil
+	}
+	var result []Source
+	ast.Inspect(expr, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.SelectorExpr:
+			if ident, ok := n.X.(*ast.Ident); ok && ident.Obj == nil {
+				return false // imported package
+			}
+			owner := g.fieldOwner(g.valueStruct(n.X, g.Pkg), n.Sel.Name, 0)
+			if owner != nil {
+				result = append(result, Source{Struct: owner.name, Field: n.Sel.Name, Pos: n.Sel.Pos()})
+				if _, ok := lineage.Fields[owner.name]; !ok {
+					lineage.Fields[owner.name] = exportedFields(owner.st)
+				}
+			}
+		case *ast.Ident:
+			if n.Obj == nil {
+				return false
+			}
+			for _, value := range assignedValues(n) {
+				result = append(result, g.valueSources(value, lineage, depth+1)...)
+			}
+		}
+		return true
+	})
+	return result
+}
+
+// assignedValues returns expressions assigned to the local variable in its declaration
+func assignedValues(ident *ast.Ident) []ast.Expr {
+	switch decl := ident.Obj.Decl.(type) {
+	case *ast.AssignStmt:
+		if len(decl.Rhs) == 1 {
+			return decl.Rhs
+		}
+		if pos := findArgumentPosition(ident.Name, decl); pos != -1 && pos < len(decl.Rhs) {
+			return []ast.Expr{decl.Rhs[pos]}
+		}
+	case *ast.ValueSpec:
+		for i, name := range decl.Names {
+			if name.Name == ident.Name && i < len(decl.Values) {
+				return []ast.Expr{decl.Values[i]}
+			}
+		}
+	}
+	return nil
+}
+
+// Lineage finds response struct fields used as values of the top-level attributes in the CRUD functions
+func (g Generator) Lineage() *Lineage {
+	lineage := &Lineage{Sources: map[string][]Source{}, Fields: map[string][]string{}}
+	for _, fn := range g.OperatingFns {
+		dName := getDName(fn)
+		ast.Inspect(fn.Body, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok || !isDataMethod(call.Fun, dName, "Set") || len(call.Args) != 2 {
+				return true
+			}
+			keyExpr, ok := call.Args[0].(*ast.BasicLit)
+			if !ok || keyExpr.Kind != token.STRING {
+				return true
+			}
+			key := strings.Trim(keyExpr.Value, `"`)
+			if _, ok := g.Schema[key]; !ok {
+				return true
+			}
+			lineage.Sources[key] = append(lineage.Sources[key], g.valueSources(call.Args[1], lineage, 0)...)
+			return true
+		})
+	}
+	for key, sources := range lineage.Sources {
+		lineage.Sources[key] = uniqueSources(sources)
+	}
+	return lineage
+}
+
+// uniqueSources removes repeated fields keeping the first usage
+func uniqueSources(sources []Source) []Source {
+	seen := map[string]bool{}
+	result := make([]Source, 0, len(sources))
+	for _, src := range sources {
+		name := core.MethodName(src.Struct, src.Field)
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		result = append(result, src)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Pos < result[j].Pos })
+	return result
+}
@@ -0,0 +1,81 @@
+package generators
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+)
+
+// validateDataSourceParity compares resources with the paired data sources
+func validateDataSourceParity(gens []*Generator, config *core.Config) error {
+	dataSources := map[string]*Generator{}
+	for _, gen := range gens {
+		if gen.Resource.DataSource {
+			dataSources[gen.Resource.TypeName] = gen
+		}
+	}
+	mErr := &multierror.Error{}
+	for _, gen := range gens {
+		if gen.Resource.TypeName == "" || gen.Resource.DataSource {
+			continue
+		}
+		ds, ok := dataSources[config.PairedDataSource(gen.Resource.TypeName)]
+		if !ok {
+			continue
+		}
+		mErr = multierror.Append(mErr, compareParity(gen, ds, gen.Schema, ds.Schema, ""))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// compareParity reports resource attributes missing in the data source and attributes with different types
+func compareParity(res, ds *Generator, resSchema, dsSchema map[string]*Field, prefix string) error {
+	const rule = core.RuleDataSourceParity
+	mErr := &multierror.Error{}
+	for _, key := range sortedKeys(resSchema) {
+		resFld := resSchema[key]
+		path := prefix + key
+		if resFld == nil || resFld.Sensitive || res.Config.IsWriteOnly(res.configName(), path) {
+			continue // secrets are not expected to be exposed by data sources
+		}
+		dsFld, ok := dsSchema[key]
+		if !ok {
+			mErr = multierror.Append(mErr, ds.ruleError(rule, ds.Pos,
+				"attribute `%s` of `%s` is missing in data source `%s`", path, res.Resource, ds.Resource))
+			continue
+		}
+		if dsFld == nil {
+			continue
+		}
+		if err := compareFields(resFld, dsFld); err != nil {
+			mErr = multierror.Append(mErr, ds.ruleError(rule, dsFld.KeyPos,
+				"attribute `%s` of data source `%s` differs from `%s`: %s", path, ds.Resource, res.Resource, err))
+			continue
+		}
+		if resFld.Schema != nil && dsFld.Schema != nil {
+			mErr = multierror.Append(mErr, compareParity(res, ds, resFld.Schema, dsFld.Schema, path+".0."))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// compareFields checks that fields have the same type and nesting
+func compareFields(resFld, dsFld *Field) error {
+	if resFld.Type == "" || dsFld.Type == "" {
+		return nil // type is unknown
+	}
+	if resFld.Type != dsFld.Type {
+		return fmt.Errorf("type is `%s` instead of `%s`", dsFld.Type, resFld.Type)
+	}
+	if (resFld.Schema == nil) != (dsFld.Schema == nil) && (resFld.Elem != nil || dsFld.Elem != nil) {
+		if resFld.Schema != nil {
+			return fmt.Errorf("elements are primitives instead of blocks")
+		}
+		return fmt.Errorf("elements are blocks instead of primitives")
+	}
+	if resFld.Elem != nil && dsFld.Elem != nil && resFld.Elem.Type != dsFld.Elem.Type {
+		return fmt.Errorf("elements type is `%s` instead of `%s`", dsFld.Elem.Type, resFld.Elem.Type)
+	}
+	return nil
+}
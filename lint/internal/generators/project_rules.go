@@ -0,0 +1,179 @@
+package generators
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+)
+
+type projectRule struct {
+	ID    string
+	Check func(gens []*Generator, config *core.Config) error
+}
+
+// projectRules are rules validating all resources of the provider together
+var projectRules = []projectRule{
+	{core.RuleAttributeConsistency, validateAttributeConsistency},
+	{core.RuleDataSourceParity, validateDataSourceParity},
+	{core.RuleExampleConfigs, validateExampleConfigs},
+}
+
+// ValidateProject runs all enabled provider-wide rules
+func ValidateProject(gens []*Generator, config *core.Config) error {
+	// sorted copy not to reorder generators of the caller
+	gens = append([]*Generator{}, gens...)
+	sort.Slice(gens, func(i, j int) bool {
+		return gens[i].position(gens[i].Pos).String() < gens[j].position(gens[j].Pos).String()
+	})
+	mErr := &multierror.Error{}
+	for _, rule := range projectRules {
+		if !config.Enabled(rule.ID) {
+			continue
+		}
+		mErr = multierror.Append(mErr, rule.Check(gens, config))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// minConsistencyGroup is minimal number of declarations to find the prevailing one
+const minConsistencyGroup = 3
+
+// declaration is a top-level attribute declared in a resource
+type declaration struct {
+	Gen   *Generator
+	Field *Field
+}
+
+// signature describes type and flags of the field, e.g. `TypeString, Optional, Computed`
+func (f *Field) signature() string {
+	parts := []string{f.Type}
+	for _, flag := range []struct {
+		name  string
+		value bool
+	}{
+		{"Required", f.Required},
+		{"Optional", f.Optional},
+		{"Computed", f.Computed},
+		{"ForceNew", f.ForceNew},
+	} {
+		if flag.value {
+			parts = append(parts, flag.name)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// attributeName returns name used to group attributes, data source attributes are grouped separately
+func attributeName(gen *Generator, key string) string {
+	if gen.Resource.DataSource {
+		return "data." + key
+	}
+	return key
+}
+
+func validateAttributeConsistency(gens []*Generator, config *core.Config) error {
+	groups := map[string][]declaration{}
+	for _, gen := range gens {
+		for key, fld := range gen.Schema {
+			if fld == nil {
+				continue
+			}
+			name := attributeName(gen, key)
+			groups[name] = append(groups[name], declaration{Gen: gen, Field: fld})
+		}
+	}
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	mErr := &multierror.Error{}
+	for _, name := range names {
+		if canonical, ok := config.Canonical[name]; ok {
+			for _, decl := range groups[name] {
+				mErr = multierror.Append(mErr, checkCanonical(name, decl, canonical))
+			}
+			continue
+		}
+		mErr = multierror.Append(mErr, checkOutliers(name, groups[name]))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// shortFuncName strips import path of the function name, e.g. `common.TagsSchema`
+func shortFuncName(name string) string {
+	return name[strings.LastIndex(name, "/")+1:]
+}
+
+func checkCanonical(name string, decl declaration, canonical core.CanonicalField) error {
+	const rule = core.RuleAttributeConsistency
+	fld := decl.Field
+	if canonical.Helper != "" && fld.Helper != canonical.Helper {
+		return decl.Gen.ruleError(rule, fld.KeyPos,
+			"attribute `%s` must be declared with `%s()`", name, shortFuncName(canonical.Helper))
+	}
+	mismatches := canonicalMismatches(fld, canonical)
+	if len(mismatches) == 0 {
+		return nil
+	}
+	return decl.Gen.ruleError(rule, fld.KeyPos,
+		"attribute `%s` is declared as `%s`, expected %s", name, fld.signature(), strings.Join(mismatches, ", "))
+}
+
+// canonicalMismatches returns the expected type and flags which don't match the field, e.g. "`Computed: true`"
+func canonicalMismatches(fld *Field, canonical core.CanonicalField) []string {
+	var mismatches []string
+	if canonical.Type != "" && fld.Type != canonical.Type {
+		mismatches = append(mismatches, fmt.Sprintf("`Type: %s`", canonical.Type))
+	}
+	for _, flag := range []struct {
+		name     string
+		expected *bool
+		actual   bool
+	}{
+		{"Required", canonical.Required, fld.Required},
+		{"Optional", canonical.Optional, fld.Optional},
+		{"Computed", canonical.Computed, fld.Computed},
+		{"ForceNew", canonical.ForceNew, fld.ForceNew},
+	} {
+		if flag.expected != nil && *flag.expected != flag.actual {
+			mismatches = append(mismatches, fmt.Sprintf("`%s: %t`", flag.name, *flag.expected))
+		}
+	}
+	return mismatches
+}
+
+// checkOutliers reports declarations different from the one used by the majority of resources
+func checkOutliers(name string, decls []declaration) error {
+	if len(decls) < minConsistencyGroup {
+		return nil
+	}
+	counts := map[string]int{}
+	for _, decl := range decls {
+		counts[decl.Field.signature()]++
+	}
+	prevailing := ""
+	for sig, count := range counts {
+		if count > counts[prevailing] || (count == counts[prevailing] && sig < prevailing) {
+			prevailing = sig
+		}
+	}
+	if counts[prevailing]*2 <= len(decls) {
+		return nil // no clear majority
+	}
+	mErr := &multierror.Error{}
+	for _, decl := range decls {
+		sig := decl.Field.signature()
+		if sig == prevailing {
+			continue
+		}
+		mErr = multierror.Append(mErr, decl.Gen.ruleError(core.RuleAttributeConsistency, decl.Field.KeyPos,
+			"attribute `%s` is declared as `%s`, while %d of %d declarations use `%s`",
+			name, sig, counts[prevailing], len(decls), prevailing))
+	}
+	return mErr.ErrorOrNil()
+}
@@ -0,0 +1,188 @@
+package generators
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+	"golang.org/x/tools/go/packages"
+)
+
+// dataFunc is a function operating with `*schema.ResourceData`
+type dataFunc struct {
+	Decl  *ast.FuncDecl
+	Pkg   *packages.Package
+	DName string // name of `*schema.ResourceData` argument
+}
+
+// reachableFns returns the function and all functions called from it with `*schema.ResourceData` argument,
+// skipped functions and functions called only from them are not included
+func (g Generator) reachableFns(fn *ast.FuncDecl, skip ...*ast.FuncDecl) []dataFunc {
+	if fn == nil || fn.Body == nil {
+		return nil
+	}
+	root := dataFunc{Decl: fn, Pkg: g.Pkg, DName: getDName(fn)}
+	if root.DName == "" {
+		return nil
+	}
+	visited := map[*ast.FuncDecl]bool{fn: true}
+	for _, decl := range skip {
+		visited[decl] = true
+	}
+	result := []dataFunc{root}
+	for i := 0; i < len(result); i++ {
+		current := result[i]
+		ast.Inspect(current.Decl.Body, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			helper, ok := g.dataHelper(call, current)
+			if !ok || visited[helper.Decl] {
+				return true
+			}
+			visited[helper.Decl] = true
+			result = append(result, helper)
+			return true
+		})
+	}
+	return result
+}
+
+// dataHelper resolves function called with `*schema.ResourceData` argument of the caller
+func (g Generator) dataHelper(call *ast.CallExpr, caller dataFunc) (dataFunc, bool) {
+	argIdx := -1
+	for i, arg := range call.Args {
+		if ident, ok := arg.(*ast.Ident); ok && ident.Name == caller.DName {
+			argIdx = i
+			break
+		}
+	}
+	if argIdx == -1 {
+		return dataFunc{}, false
+	}
+	decl, pkg := g.calledFunction(call, caller.Pkg)
+	if decl == nil || decl.Body == nil {
+		return dataFunc{}, false
+	}
+	name := paramName(decl, argIdx)
+	if name == "" || name == "_" {
+		return dataFunc{}, false
+	}
+	return dataFunc{Decl: decl, Pkg: pkg, DName: name}, true
+}
+
+// calledFunction finds declaration of the package-level function called in the given package
+func (g Generator) calledFunction(call *ast.CallExpr, pkg *packages.Package) (*ast.FuncDecl, *packages.Package) {
+	switch fn := call.Fun.(type) {
+	case *ast.Ident:
+		scope, err := g.getCachedScope(pkg)
+		if err != nil {
+			return nil, nil
+		}
+		return scope.FuncDecls[fn.Name], pkg
+	case *ast.SelectorExpr:
+		x, ok := fn.X.(*ast.Ident)
+		if !ok || x.Obj != nil {
+			return nil, nil // method call
+		}
+		path := g.absoluteImport(x.Name, fn, pkg)
+		if path == "" {
+			return nil, nil
+		}
+		imp, err := importByName(pkg, path)
+		if err != nil {
+			return nil, nil
+		}
+		scope, err := g.getCachedScope(imp)
+		if err != nil {
+			return nil, nil
+		}
+		return scope.FuncDecls[fn.Sel.Name], imp
+	}
+	return nil, nil
+}
+
+// paramName returns name of the function parameter by its index
+func paramName(decl *ast.FuncDecl, idx int) string {
+	i := 0
+	for _, fld := range decl.Type.Params.List {
+		if len(fld.Names) == 0 {
+			if i == idx {
+				return ""
+			}
+			i++
+			continue
+		}
+		for _, name := range fld.Names {
+			if i == idx {
+				return name.Name
+			}
+			i++
+		}
+	}
+	return ""
+}
+
+// dataCalls finds calls of `*schema.ResourceData` methods with the given names
+// and a string literal key as a first argument, e.g. `d.Set("name", ...)`
+func dataCalls(fns []dataFunc, methods ...string) map[string][]*ast.CallExpr {
+	result := map[string][]*ast.CallExpr{}
+	for _, fn := range fns {
+		ast.Inspect(fn.Decl.Body, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok || !isDataMethod(call.Fun, fn.DName, methods...) || len(call.Args) == 0 {
+				return true
+			}
+			lit, ok := call.Args[0].(*ast.BasicLit)
+			if !ok {
+				return true
+			}
+			key, _ := core.UnwrapString(lit)
+			result[key] = append(result[key], call)
+			return true
+		})
+	}
+	return result
+}
+
+// dataKeys finds string literal arguments of `*schema.ResourceData` methods with the given names,
+// e.g. both keys of `d.HasChanges("name", "description")`
+func dataKeys(fns []dataFunc, methods ...string) map[string][]*ast.BasicLit {
+	result := map[string][]*ast.BasicLit{}
+	for _, fn := range fns {
+		ast.Inspect(fn.Decl.Body, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok || !isDataMethod(call.Fun, fn.DName, methods...) {
+				return true
+			}
+			for _, arg := range call.Args {
+				lit, ok := arg.(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					continue
+				}
+				key, _ := core.UnwrapString(lit)
+				result[key] = append(result[key], lit)
+			}
+			return true
+		})
+	}
+	return result
+}
+
+func isDataMethod(expr ast.Expr, dName string, methods ...string) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	if !ok || ident.Name != dName {
+		return false
+	}
+	for _, m := range methods {
+		if sel.Sel.Name == m {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package generators
+
+import (
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+)
+
+// ValidateReadCompleteness checks that every computed field is set in `Read` or the functions it calls
+func (g Generator) ValidateReadCompleteness() error {
+	read, ok := g.Operations["Read"]
+	if !ok || read == nil {
+		return nil
+	}
+	readSetters := dataCalls(g.reachableFns(read), "Set")
+	createSetters := dataCalls(g.reachableFns(g.Operations["Create"]), "Set")
+
+	mErr := &multierror.Error{}
+	for _, key := range sortedKeys(g.Schema) {
+		fld := g.Schema[key]
+		if fld == nil || !fld.Computed || g.Config.IsWriteOnly(g.configName(), key) {
+			continue
+		}
+		if _, ok := readSetters[key]; ok {
+			continue
+		}
+		if calls, ok := createSetters[key]; ok {
+			mErr = multierror.Append(mErr, g.ruleError(core.RuleReadCompleteness, calls[0].Pos(),
+				"computed field `%s` is set only in `Create`, the value is lost on import or refresh", key))
+			continue
+		}
+		mErr = multierror.Append(mErr, g.ruleError(core.RuleReadCompleteness, fld.Pos,
+			"computed field `%s` is never set in `Read`", key))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// ValidateImportCompleteness checks that configurable fields of the resource with an `Importer`
+// are set in `Read` or in the importer, otherwise they are empty after import
+func (g Generator) ValidateImportCompleteness() error {
+	importer, ok := g.Operations["Import"]
+	if !ok {
+		return nil
+	}
+	fns := append(g.reachableFns(g.Operations["Read"]), g.reachableFns(importer)...)
+	setters := dataCalls(fns, "Set")
+	steps := g.importSteps()
+
+	mErr := &multierror.Error{}
+	for _, key := range sortedKeys(g.Schema) {
+		fld := g.Schema[key]
+		if fld == nil || !(fld.Required || fld.Optional) || g.Config.IsWriteOnly(g.configName(), key) {
+			continue
+		}
+		if _, ok := setters[key]; ok {
+			continue
+		}
+		if len(steps) == 0 {
+			mErr = multierror.Append(mErr, g.ruleError(core.RuleImportCompleteness, fld.Pos,
+				"field `%s` is not set in `Read` or the importer and is empty after import", key))
+			continue
+		}
+		if !ignoredOnImport(steps, key) {
+			mErr = multierror.Append(mErr, g.ruleError(core.RuleImportCompleteness, fld.Pos,
+				"field `%s` is not set in `Read` or the importer and is empty after import, "+
+					"but it is missing in `ImportStateVerifyIgnore` of the acceptance test", key))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// importSteps returns acceptance test steps importing the resource
+func (g Generator) importSteps() []core.ImportStep {
+	if g.Resource.TypeName == "" || g.Resource.DataSource {
+		return nil
+	}
+	return g.Project.ImportSteps[g.Resource.TypeName]
+}
+
+// ignoredOnImport checks if the field or any of its nested fields is ignored in every import step
+func ignoredOnImport(steps []core.ImportStep, key string) bool {
+	for _, step := range steps {
+		found := false
+		for _, ref := range step.Ignore {
+			if ref.Key == key || strings.HasPrefix(ref.Key, key+".") {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
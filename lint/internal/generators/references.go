@@ -0,0 +1,142 @@
+package generators
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+)
+
+// selfReferenceAllowed shows if the relation list can contain the field itself
+var selfReferenceAllowed = map[string]bool{
+	"ConflictsWith": false,
+	"ExactlyOneOf":  true,
+	"AtLeastOneOf":  true,
+	"RequiredWith":  true,
+}
+
+// sortedKeys returns schema keys in stable order, so the errors are reproducible
+func sortedKeys(schema map[string]*Field) []string {
+	keys := make([]string, 0, len(schema))
+	for k := range schema {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// walkSchema calls fn for every field of the schema, including nested ones,
+// passing absolute path of the field, e.g. `block.0.field`
+func walkSchema(schema map[string]*Field, prefix string, fn func(path string, fld *Field)) {
+	for _, key := range sortedKeys(schema) {
+		fld := schema[key]
+		if fld == nil {
+			continue
+		}
+		path := prefix + key
+		fn(path, fld)
+		if fld.Schema != nil {
+			walkSchema(fld.Schema, path+".0.", fn)
+		}
+	}
+}
+
+// lookupPath finds the field by absolute path the same way as SDK does for schema relations
+func (g Generator) lookupPath(path string) (*Field, error) {
+	parts := strings.Split(path, ".")
+	current := g.Schema
+	var target *Field
+	for i, part := range parts {
+		if idx, err := strconv.Atoi(part); err == nil {
+			if idx != 0 {
+				return nil, fmt.Errorf("configuration block reference can only use the `.0.` index")
+			}
+			continue
+		}
+		fld, ok := current[part]
+		if !ok || fld == nil {
+			return nil, fmt.Errorf("unknown attribute at part `%s`", part)
+		}
+		target = fld
+		if i == len(parts)-1 {
+			break
+		}
+		if fld.Schema == nil {
+			return nil, fmt.Errorf("attribute `%s` has no nested schema", part)
+		}
+		if (fld.Type == "TypeSet" || fld.MaxItems != 1) && i+1 != len(parts)-1 {
+			return nil, fmt.Errorf(
+				"configuration block reference can only be used with `TypeList` and `MaxItems: 1` blocks",
+			)
+		}
+		current = fld.Schema
+	}
+	if target == nil {
+		return nil, fmt.Errorf("can't find target attribute")
+	}
+	return target, nil
+}
+
+func hasReference(refs []core.Reference, key string) bool {
+	for _, r := range refs {
+		if r.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateSchemaReferences checks that keys used in the schema relations
+// (`ConflictsWith`, `ExactlyOneOf`, `AtLeastOneOf`, `RequiredWith`) are consistent
+func (g Generator) ValidateSchemaReferences() error {
+	mErr := &multierror.Error{}
+	walkSchema(g.Schema, "", func(path string, fld *Field) {
+		for _, relation := range sortedRelations(fld) {
+			for _, ref := range fld.Relations[relation] {
+				mErr = multierror.Append(mErr, g.validateReference(path, fld, relation, ref))
+			}
+		}
+	})
+	return mErr.ErrorOrNil()
+}
+
+func sortedRelations(fld *Field) []string {
+	names := make([]string, 0, len(fld.Relations))
+	for k := range fld.Relations {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (g Generator) validateReference(path string, fld *Field, relation string, ref core.Reference) error {
+	const rule = core.RuleSchemaReferences
+	target, err := g.lookupPath(ref.Key)
+	if err != nil {
+		return g.ruleError(rule, ref.Pos, "`%s` of field `%s` references `%s`: %s", relation, path, ref.Key, err)
+	}
+	if target == fld {
+		if selfReferenceAllowed[relation] {
+			return nil
+		}
+		return g.ruleError(rule, ref.Pos, "`%s` of field `%s` references the field itself", relation, path)
+	}
+	if target.Required {
+		return g.ruleError(rule, ref.Pos, "`%s` of field `%s` references required field `%s`", relation, path, ref.Key)
+	}
+	if relation != "ConflictsWith" {
+		return nil
+	}
+	if fld.Required {
+		return g.ruleError(rule, ref.Pos,
+			"required field `%s` conflicts with `%s` and can never be satisfied", path, ref.Key)
+	}
+	if !hasReference(target.Relations[relation], path) {
+		return g.ruleError(rule, ref.Pos,
+			"field `%s` conflicts with `%s`, but `%s` doesn't conflict with `%s`", path, ref.Key, ref.Key, path)
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package generators
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+)
+
+// schemaAssertions are types `d.Get` values of the schema types are asserted to, without package names
+var schemaAssertions = map[string]string{
+	"TypeString": "string",
+	"TypeInt":    "int",
+	"TypeBool":   "bool",
+	"TypeFloat":  "float64",
+	"TypeList":   "[]interface{}",
+	"TypeSet":    "*Set",
+	"TypeMap":    "map[string]interface{}",
+}
+
+// qualifierRe matches package names of the qualified type names
+var qualifierRe = regexp.MustCompile(`\w+\.`)
+
+// typeString returns type expression without package names, e.g. `*Set` for `*schema.Set`
+func typeString(expr ast.Expr) string {
+	return qualifierRe.ReplaceAllString(types.ExprString(expr), "")
+}
+
+// isBuiltinType checks if the type expression uses only builtin types, e.g. `[]string` or `*int`
+func isBuiltinType(expr ast.Expr) bool {
+	builtin := true
+	ast.Inspect(expr, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.SelectorExpr, *ast.FuncType, *ast.ChanType, *ast.StructType:
+			builtin = false
+		case *ast.Ident:
+			if builtIns.Lookup(n.Name) == nil {
+				builtin = false
+			}
+		}
+		return builtin
+	})
+	return builtin
+}
+
+// dataValue is a value read with `d.Get` or `d.GetOk`
+type dataValue struct {
+	Key string
+	// Asserted is the type `d.Get` result is asserted to
+	Asserted ast.Expr
+	// Type is the type of the whole expression, nil if it's unknown, e.g. after a function call
+	Type ast.Expr
+}
+
+// dataKey returns the key of the `d.Get` or `d.GetOk` call, local variables are traced to their assignments
+func dataKey(expr ast.Expr, dName string, depth int) string {
+	switch e := expr.(type) {
+	case *ast.CallExpr:
+		if !isDataMethod(e.Fun, dName, "Get", "GetOk") || len(e.Args) != 1 {
+			return ""
+		}
+		if lit, ok := e.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			key, _ := strconv.Unquote(lit.Value)
+			return key
+		}
+	case *ast.Ident:
+		if e.Obj == nil || depth > maxValueDepth {
+			return ""
+		}
+		if values := assignedValues(e); len(values) == 1 {
+			return dataKey(values[0], dName, depth+1)
+		}
+	}
+	return ""
+}
+
+// conversionTypes are builtin types used in type conversions of the `d.Get` values
+var conversionTypes = map[string]bool{
+	"string": true, "bool": true, "float32": true, "float64": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+}
+
+// findDataValue follows the `d.Get` value through assertions, local variables, pointers and type conversions
+func findDataValue(expr ast.Expr, dName string, depth int) *dataValue {
+	if depth > maxValueDepth {
+		return nil
+	}
+	switch e := expr.(type) {
+	case *ast.TypeAssertExpr:
+		if e.Type == nil {
+			return nil
+		}
+		if key := dataKey(e.X, dName, depth); key != "" {
+			return &dataValue{Key: key, Asserted: e.Type, Type: e.Type}
+		}
+	case *ast.ParenExpr:
+		return findDataValue(e.X, dName, depth)
+	case *ast.UnaryExpr:
+		value := findDataValue(e.X, dName, depth)
+		if value != nil && value.Type != nil && e.Op == token.AND {
+			value.Type = &ast.StarExpr{X: value.Type}
+		}
+		return value
+	case *ast.Ident:
+		if e.Obj == nil {
+			return nil
+		}
+		if values := assignedValues(e); len(values) == 1 {
+			return findDataValue(values[0], dName, depth+1)
+		}
+	case *ast.CallExpr:
+		if ident, ok := e.Fun.(*ast.Ident); ok && conversionTypes[ident.Name] && len(e.Args) == 1 {
+			if value := findDataValue(e.Args[0], dName, depth); value != nil {
+				value.Type = ident
+				return value
+			}
+			return nil
+		}
+		for _, arg := range e.Args { // result type of other calls is unknown
+			if value := findDataValue(arg, dName, depth); value != nil {
+				value.Type = nil
+				return value
+			}
+		}
+	}
+	return nil
+}
+
+// schemaField finds the field by the full key, e.g. `rule.0.port`
+func (g Generator) schemaField(key string) *Field {
+	schema := g.Schema
+	var fld *Field
+	for _, part := range strings.Split(key, ".") {
+		if _, err := strconv.Atoi(part); err == nil && fld != nil {
+			continue // list index
+		}
+		if schema == nil {
+			return nil
+		}
+		fld = schema[part]
+		if fld == nil {
+			return nil
+		}
+		schema = fld.Schema
+	}
+	return fld
+}
+
+// fieldTypeExpr returns declared type of the struct field
+func fieldTypeExpr(decl *structDecl, name string) ast.Expr {
+	for _, fld := range decl.st.Fields.List {
+		for _, n := range fld.Names {
+			if n.Name == name {
+				return fld.Type
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateRequestTypes checks that `d.Get` values assigned to fields of the imported structs, e.g. `CreateOpts`,
+// are asserted to the types matching the schema and have the types of the destination fields
+func (g Generator) ValidateRequestTypes() error {
+	mErr := &multierror.Error{}
+	for _, fn := range g.OperatingFns {
+		dName := getDName(fn)
+		if dName == "" {
+			continue
+		}
+		ast.Inspect(fn.Body, func(node ast.Node) bool {
+			lit, ok := node.(*ast.CompositeLit)
+			if !ok || lit.Type == nil {
+				return true
+			}
+			mErr = multierror.Append(mErr, g.checkRequestLiteral(lit, dName))
+			return true
+		})
+	}
+	return mErr.ErrorOrNil()
+}
+
+func (g Generator) checkRequestLiteral(lit *ast.CompositeLit, dName string) error {
+	typ, err := g.getExpType(lit.Type, g.Pkg)
+	if err != nil || typ.Package() == "" || typ.Package() == g.Pkg.ID {
+		return nil
+	}
+	decl := g.namedStruct(typ, g.Pkg)
+	if decl == nil {
+		return nil
+	}
+	mErr := &multierror.Error{}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		value := findDataValue(kv.Value, dName, 0)
+		if value == nil {
+			continue
+		}
+		if fld := g.schemaField(value.Key); fld != nil {
+			expected, known := schemaAssertions[fld.Type]
+			if actual := typeString(value.Asserted); known && actual != expected {
+				mErr = multierror.Append(mErr, g.ruleError(core.RuleRequestTypes, kv.Value.Pos(),
+					"`%s` is `%s`, but its value is asserted to `%s`", value.Key, fld.Type, actual))
+				continue
+			}
+		}
+		if value.Type == nil {
+			continue
+		}
+		owner := g.fieldOwner(decl, key.Name, 0)
+		if owner == nil {
+			continue
+		}
+		fieldType := fieldTypeExpr(owner, key.Name)
+		if fieldType == nil || !isBuiltinType(fieldType) || !isBuiltinType(value.Type) {
+			continue
+		}
+		if _, ok := fieldType.(*ast.InterfaceType); ok {
+			continue
+		}
+		if actual, expected := typeString(value.Type), typeString(fieldType); actual != expected {
+			mErr = multierror.Append(mErr, g.ruleError(core.RuleRequestTypes, kv.Value.Pos(),
+				"value of `%s` is `%s`, but field `%s` of `%s` is `%s`",
+				value.Key, actual, key.Name, shortName(owner.name), expected))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// shortName returns the full name with the package name instead of the import path, e.g. `servers.CreateOpts`
+func shortName(name string) string {
+	slash := strings.LastIndex(name, "/")
+	return name[slash+1:]
+}
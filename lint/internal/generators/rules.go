@@ -0,0 +1,61 @@
+package generators
+
+import (
+	"fmt"
+	"go/token"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+)
+
+type generatorRule struct {
+	ID    string
+	Check func(g Generator) error
+}
+
+// generatorRules are rules validating single resource
+var generatorRules = []generatorRule{
+	{core.RuleSetters, Generator.ValidateSetters},
+	{core.RuleSchemaReferences, Generator.ValidateSchemaReferences},
+	{core.RuleSchemaMode, schemaRule(checkSchemaMode)},
+	{core.RuleSchemaRequiredComputed, schemaRule(checkRequiredComputed)},
+	{core.RuleSchemaDefault, schemaRule(checkDefault)},
+	{core.RuleSchemaElem, schemaRule(checkElem)},
+	{core.RuleSchemaMaxItems, schemaRule(checkMaxItems)},
+	{core.RuleSchemaComputedForceNew, schemaRule(checkComputedForceNew)},
+	{core.RuleResourceUpdate, Generator.ValidateUpdate},
+	{core.RuleSchemaValueTypes, Generator.ValidateValueTypes},
+	{core.RuleReadCompleteness, Generator.ValidateReadCompleteness},
+	{core.RuleImportCompleteness, Generator.ValidateImportCompleteness},
+	{core.RuleTestAttributes, Generator.ValidateTestAttributes},
+	{core.RuleUpdateCoverage, Generator.ValidateUpdateCoverage},
+	{core.RuleSensitiveLeak, Generator.ValidateSensitiveLeaks},
+	{core.RuleDocsDrift, Generator.ValidateDocs},
+	{core.RuleRequestTypes, Generator.ValidateRequestTypes},
+	{core.RuleHelperKeys, Generator.ValidateHelperKeys},
+	{core.RuleDeletedResources, Generator.ValidateDeletedHandling},
+	{core.RuleImplicitGetters, Generator.ValidateImplicitGetters},
+}
+
+// Validate runs all enabled rules for the generator
+func (g Generator) Validate() error {
+	mErr := &multierror.Error{}
+	for _, rule := range generatorRules {
+		if !g.Config.Enabled(rule.ID) {
+			continue
+		}
+		mErr = multierror.Append(mErr, rule.Check(g))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// RuleError creates an error reported by the rule implemented outside the package
+func (g Generator) RuleError(rule string, pos token.Pos, format string, args ...interface{}) error {
+	return g.ruleError(rule, pos, format, args...)
+}
+
+// ruleError creates an error reported by the rule
+func (g Generator) ruleError(rule string, pos token.Pos, format string, args ...interface{}) error {
+	msg := fmt.Sprintf(format, args...)
+	return fmt.Errorf("%s - %s [%s]", g.position(pos).String(), msg, rule)
+}
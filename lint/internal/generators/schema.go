@@ -3,6 +3,8 @@ package generators
 import (
 	"fmt"
 	"go/ast"
+	"go/token"
+	"strconv"
 
 	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
 	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/set"
@@ -17,14 +19,129 @@ var usedFnNames = set.StringSetFromSlice([]string{
 	"Update",
 })
 
+// operationNames maps resource CRUD fields to operation names
+var operationNames = map[string]string{
+	"Create":        "Create",
+	"CreateContext": "Create",
+	"Read":          "Read",
+	"ReadContext":   "Read",
+	"Update":        "Update",
+	"UpdateContext": "Update",
+	"Delete":        "Delete",
+	"DeleteContext": "Delete",
+}
+
+// registerOperation remembers CRUD function, declaration is nil if it can't be resolved
+func (g *Generator) registerOperation(key string, value ast.Expr) {
+	op, ok := operationNames[key]
+	if !ok || !g.isSupportedField(key) {
+		return
+	}
+	var decl *ast.FuncDecl
+	if ident, ok := value.(*ast.Ident); ok && ident.Obj != nil {
+		decl, _ = ident.Obj.Decl.(*ast.FuncDecl)
+	}
+	g.Operations[op] = decl
+}
+
+// registerImporter remembers the resource importer as an `Import` operation,
+// declaration is nil for SDK importers, e.g. `schema.ImportStatePassthroughContext`
+func (g *Generator) registerImporter(value ast.Expr) {
+	if unary, ok := value.(*ast.UnaryExpr); ok {
+		value = unary.X
+	}
+	lit, ok := value.(*ast.CompositeLit)
+	if !ok {
+		return
+	}
+	g.Operations["Import"] = nil
+	for _, el := range lit.Elts {
+		kv, ok := el.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok || (key.Name != "State" && key.Name != "StateContext") || !g.isSupportedField(key.Name) {
+			continue
+		}
+		if ident, ok := kv.Value.(*ast.Ident); ok && ident.Obj != nil {
+			g.Operations["Import"], _ = ident.Obj.Decl.(*ast.FuncDecl)
+		}
+	}
+}
+
+// intValue returns value of the integer literal, -1 is returned for other expressions
+func intValue(expr ast.Expr) int {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.INT {
+		return -1
+	}
+	v, err := strconv.Atoi(lit.Value)
+	if err != nil {
+		return -1
+	}
+	return v
+}
+
+// registerStateUpgraders remembers `StateUpgraders` entries declared as a slice literal
+func (g *Generator) registerStateUpgraders(value ast.Expr) {
+	lit, ok := value.(*ast.CompositeLit)
+	if !ok {
+		return
+	}
+	for _, el := range lit.Elts {
+		entry, ok := el.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+		upgrader := StateUpgrader{Version: -1, Pos: entry.Pos()}
+		for _, el := range entry.Elts {
+			kv, ok := el.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			key, ok := kv.Key.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			switch key.Name {
+			case "Version":
+				upgrader.Version = intValue(kv.Value)
+			case "Upgrade":
+				upgrader.Upgrade = g.localFunction(kv.Value)
+			}
+		}
+		g.StateUpgraders = append(g.StateUpgraders, upgrader)
+	}
+}
+
+// localFunction returns declaration of the package function referenced by the identifier
+func (g Generator) localFunction(expr ast.Expr) *ast.FuncDecl {
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	if ident.Obj != nil {
+		decl, _ := ident.Obj.Decl.(*ast.FuncDecl)
+		return decl
+	}
+	scope, err := g.getCachedScope(g.Pkg)
+	if err != nil {
+		return nil
+	}
+	return scope.FuncDecls[ident.Name]
+}
+
 func (g *Generator) LoadSchema(lit *ast.CompositeLit) error {
+	g.Pos = lit.Pos()
 	for _, el := range lit.Elts {
 		kv, ok := el.(*ast.KeyValueExpr)
 		if !ok {
 			continue
 		}
 		key := kv.Key.(*ast.Ident)
-		if usedFnNames.Contains(key.Name) {
+		g.registerOperation(key.Name, kv.Value)
+		if usedFnNames.Contains(key.Name) && g.isSupportedField(key.Name) {
 			ident := kv.Value.(*ast.Ident)
 			if ident.Obj == nil {
 				continue
@@ -33,6 +150,18 @@ func (g *Generator) LoadSchema(lit *ast.CompositeLit) error {
 			g.OperatingFns = append(g.OperatingFns, fnDecl)
 			continue
 		}
+		if key.Name == "Importer" {
+			g.registerImporter(kv.Value)
+			continue
+		}
+		if key.Name == "SchemaVersion" {
+			g.SchemaVersion = intValue(kv.Value)
+			continue
+		}
+		if key.Name == "StateUpgraders" {
+			g.registerStateUpgraders(kv.Value)
+			continue
+		}
 		if key.Name == "Schema" {
 			cmp, ok := kv.Value.(*ast.CompositeLit)
 			if !ok {
@@ -60,6 +189,9 @@ func (g Generator) schemaDeclToMap(schemaDecl *ast.CompositeLit) (map[string]*Fi
 		if err != nil {
 			return nil, err
 		}
+		if val != nil {
+			val.KeyPos = kv.Key.Pos()
+		}
 		result[key] = val
 	}
 	return result, nil
@@ -68,33 +200,173 @@ func (g Generator) schemaDeclToMap(schemaDecl *ast.CompositeLit) (map[string]*Fi
 func (g Generator) parseSchemaField(expr ast.Expr) (*Field, error) {
 	switch v := expr.(type) {
 	case *ast.CompositeLit:
-		return parseComposite(v)
+		return g.parseComposite(v)
 	case *ast.CallExpr:
 		return g.parseFieldGenCall(v)
 	}
 	return nil, fmt.Errorf("invalid field %+v", expr)
 }
 
-func parseComposite(lit *ast.CompositeLit) (*Field, error) {
-	f := &Field{}
+// relationKeys are the schema fields containing lists of other schema keys
+var relationKeys = set.StringSetFromSlice([]string{
+	"ConflictsWith",
+	"ExactlyOneOf",
+	"AtLeastOneOf",
+	"RequiredWith",
+})
+
+func isTrue(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "true"
+}
+
+func parseReferences(expr ast.Expr) []core.Reference {
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil // not a literal list, can't check it statically
+	}
+	refs := make([]core.Reference, 0, len(lit.Elts))
+	for _, el := range lit.Elts {
+		bl, ok := el.(*ast.BasicLit)
+		if !ok || bl.Kind != token.STRING {
+			continue
+		}
+		key, _ := core.UnwrapString(bl)
+		refs = append(refs, core.Reference{Key: key, Pos: bl.Pos()})
+	}
+	return refs
+}
+
+func (g Generator) parseComposite(lit *ast.CompositeLit) (*Field, error) { //nolint:cyclop
+	f := &Field{Pos: lit.Pos(), Attrs: map[string]token.Pos{}, Relations: map[string][]core.Reference{}}
 	for i, el := range lit.Elts {
 		kv, ok := el.(*ast.KeyValueExpr)
 		if !ok {
 			return nil, fmt.Errorf("error processing element #%d of a composite", i)
 		}
 		name := kv.Key.(*ast.Ident).Name
-		if name == "Type" {
+		f.Attrs[name] = kv.Pos()
+		switch name {
+		case "Type":
 			val, ok := kv.Value.(*ast.SelectorExpr)
 			if !ok {
 				return nil, fmt.Errorf("invalid `Type` field of %s", name)
 			}
 			f.Type = val.Sel.String()
+		case "Required":
+			f.Required = isTrue(kv.Value)
+		case "Optional":
+			f.Optional = isTrue(kv.Value)
+		case "Computed":
+			f.Computed = isTrue(kv.Value)
+		case "ForceNew":
+			f.ForceNew = isTrue(kv.Value)
+		case "Sensitive":
+			f.Sensitive = isTrue(kv.Value)
+		case "Deprecated":
+			f.Deprecated = deprecationMessage(kv.Value)
+		case "Description":
+			f.Description = stringValue(kv.Value)
+		case "Default":
+			f.Default = kv.Value
+		case "MaxItems":
+			if bl, ok := kv.Value.(*ast.BasicLit); ok {
+				f.MaxItems, _ = strconv.Atoi(bl.Value)
+			}
+		case "Elem":
+			g.parseElem(kv.Value, f)
+		default:
+			if g.features().Validators.Contains(name) {
+				f.Validators = append(f.Validators, kv.Value)
+			}
+			if relationKeys.Contains(name) {
+				f.Relations[name] = parseReferences(kv.Value)
+			}
 		}
 	}
 	return f, nil
 }
 
-func (g Generator) parseImportedFieldGenFn(expr *ast.SelectorExpr) (*Field, error) {
+// stringValue returns value of the string literal or concatenation of literals, empty string for other expressions
+func stringValue(expr ast.Expr) string {
+	switch v := expr.(type) {
+	case *ast.BasicLit:
+		if v.Kind != token.STRING {
+			return ""
+		}
+		s, _ := strconv.Unquote(v.Value)
+		return s
+	case *ast.BinaryExpr:
+		if v.Op != token.ADD {
+			return ""
+		}
+		x, y := stringValue(v.X), stringValue(v.Y)
+		if x == "" || y == "" {
+			return ""
+		}
+		return x + y
+	case *ast.ParenExpr:
+		return stringValue(v.X)
+	}
+	return ""
+}
+
+// deprecationMessage returns value of the `Deprecated` message
+func deprecationMessage(expr ast.Expr) string {
+	if msg := stringValue(expr); msg != "" {
+		return msg
+	}
+	return "deprecated"
+}
+
+// parseElem fills either field `Elem` or nested `Schema`, both are left unset
+// if the element can't be resolved statically, so the element is unknown
+func (g Generator) parseElem(expr ast.Expr, f *Field) {
+	var cmp *ast.CompositeLit
+	switch v := expr.(type) {
+	case *ast.UnaryExpr:
+		cmp, _ = v.X.(*ast.CompositeLit)
+	case *ast.CompositeLit:
+		cmp = v
+	case *ast.CallExpr:
+		decl, err := g.fnDeclForCall(v)
+		if err != nil {
+			return
+		}
+		cmp, _ = returnedComposite(decl)
+	}
+	if cmp == nil {
+		return
+	}
+	sel, ok := cmp.Type.(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	if sel.Sel.Name != "Resource" {
+		if elem, err := g.parseComposite(cmp); err == nil {
+			f.Elem = elem
+		}
+		return
+	}
+	for _, el := range cmp.Elts {
+		kv, ok := el.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if key, ok := kv.Key.(*ast.Ident); !ok || key.Name != "Schema" {
+			continue
+		}
+		sch, ok := kv.Value.(*ast.CompositeLit)
+		if !ok {
+			return // e.g. schema declared as a variable
+		}
+		if nested, err := g.schemaDeclToMap(sch); err == nil {
+			f.Schema = nested
+		}
+	}
+}
+
+func (g Generator) importedFnDecl(expr *ast.SelectorExpr) (*ast.FuncDecl, error) {
 	pkgName := expr.X.(*ast.Ident).Name
 	absImport := g.absoluteImport(pkgName, expr, g.Pkg)
 	if absImport == "" {
@@ -113,10 +385,11 @@ func (g Generator) parseImportedFieldGenFn(expr *ast.SelectorExpr) (*Field, erro
 	if !ok {
 		return nil, fmt.Errorf("can't find function with name `%s` in package `%s`", fnName, pkgName)
 	}
-	return parseFnDeclaration(fnDecl)
+	return fnDecl, nil
 }
 
-func parseFnDeclaration(decl *ast.FuncDecl) (*Field, error) {
+// returnedComposite finds composite literal returned by the function
+func returnedComposite(decl *ast.FuncDecl) (*ast.CompositeLit, error) {
 	for _, stmt := range decl.Body.List {
 		ret, ok := stmt.(*ast.ReturnStmt)
 		if !ok {
@@ -130,10 +403,16 @@ func parseFnDeclaration(decl *ast.FuncDecl) (*Field, error) {
 		switch r := result.(type) {
 		case *ast.UnaryExpr:
 			// value defined in the return
-			cmp = r.X.(*ast.CompositeLit)
+			cmp, ok = r.X.(*ast.CompositeLit)
+			if !ok {
+				return nil, fmt.Errorf("unknown kind of returned value")
+			}
 		case *ast.Ident:
 			// if function returns some variable
 			// find the declaration
+			if r.Obj == nil {
+				return nil, fmt.Errorf("can't find declaration of returned `%s`", r.Name)
+			}
 			ass, ok := r.Obj.Decl.(*ast.AssignStmt)
 			if !ok {
 				return nil, fmt.Errorf("unknown kind of var assignment")
@@ -143,25 +422,56 @@ func parseFnDeclaration(decl *ast.FuncDecl) (*Field, error) {
 				return nil, fmt.Errorf("too complex assignment :(")
 			}
 			// get the value and hope it's a unary expression now
-			val := ass.Rhs[0].(*ast.UnaryExpr)
+			val, ok := ass.Rhs[0].(*ast.UnaryExpr)
+			if !ok {
+				return nil, fmt.Errorf("unknown kind of assigned value")
+			}
 			// do the same as in the previous case
-			cmp = val.X.(*ast.CompositeLit)
+			cmp, ok = val.X.(*ast.CompositeLit)
+			if !ok {
+				return nil, fmt.Errorf("unknown kind of assigned value")
+			}
 		default:
 			return nil, fmt.Errorf("unknown kind of return: %v", r)
 		}
-		return parseComposite(cmp)
+		return cmp, nil
 	}
 	return nil, nil
 }
 
-func (g Generator) parseFieldGenCall(call *ast.CallExpr) (*Field, error) {
+func (g Generator) parseFnDeclaration(decl *ast.FuncDecl) (*Field, error) {
+	cmp, err := returnedComposite(decl)
+	if err != nil || cmp == nil {
+		return nil, err
+	}
+	return g.parseComposite(cmp)
+}
+
+func (g Generator) fnDeclForCall(call *ast.CallExpr) (*ast.FuncDecl, error) {
 	switch fn := call.Fun.(type) {
 	case *ast.Ident:
-		// in package
-		return parseFnDeclaration(fn.Obj.Decl.(*ast.FuncDecl))
+		// in package, possibly declared in another file
+		decl := g.localFunction(fn)
+		if decl == nil {
+			return nil, fmt.Errorf("can't find function declaration of `%s`", fn.Name)
+		}
+		return decl, nil
 	case *ast.SelectorExpr:
 		// imported
-		return g.parseImportedFieldGenFn(fn)
+		return g.importedFnDecl(fn)
 	}
-	return nil, fmt.Errorf("error parsing generator field function call: unknown type of function")
+	return nil, fmt.Errorf("unknown type of function")
+}
+
+func (g Generator) parseFieldGenCall(call *ast.CallExpr) (*Field, error) {
+	decl, err := g.fnDeclForCall(call)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing generator field function call: %w", err)
+	}
+	f, err := g.parseFnDeclaration(decl)
+	if err != nil || f == nil {
+		return f, err
+	}
+	f.Helper = g.funcFullName(call.Fun)
+	return f, nil
 }
@@ -0,0 +1,144 @@
+package generators
+
+import (
+	"go/token"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+)
+
+// fieldCheck validates single field found by the given path
+type fieldCheck func(g Generator, path string, fld *Field) error
+
+// schemaRule creates a rule running the check for every field of the schema
+func schemaRule(check fieldCheck) func(g Generator) error {
+	return func(g Generator) error {
+		mErr := &multierror.Error{}
+		walkSchema(g.Schema, "", func(path string, fld *Field) {
+			mErr = multierror.Append(mErr, check(g, path, fld))
+		})
+		return mErr.ErrorOrNil()
+	}
+}
+
+// attrPos returns position of the field attribute, or of the field itself if the attribute is not set
+func (f *Field) attrPos(name string) token.Pos {
+	if pos, ok := f.Attrs[name]; ok {
+		return pos
+	}
+	return f.Pos
+}
+
+func (f *Field) hasAttr(name string) bool {
+	_, ok := f.Attrs[name]
+	return ok
+}
+
+func (f *Field) isCollection() bool {
+	return f.Type == "TypeList" || f.Type == "TypeSet"
+}
+
+// computedOnly shows if the field can't be set by user
+func (f *Field) computedOnly() bool {
+	return f.Computed && !f.Optional && !f.Required
+}
+
+func checkSchemaMode(g Generator, path string, fld *Field) error {
+	if fld.Required && fld.Optional {
+		return g.ruleError(core.RuleSchemaMode, fld.attrPos("Optional"),
+			"field `%s` is both `Required` and `Optional`", path)
+	}
+	if !fld.Required && !fld.Optional && !fld.Computed {
+		return g.ruleError(core.RuleSchemaMode, fld.Pos,
+			"one of `Optional`, `Required` or `Computed` must be set for field `%s`", path)
+	}
+	return nil
+}
+
+func checkRequiredComputed(g Generator, path string, fld *Field) error {
+	if fld.Required && fld.Computed {
+		return g.ruleError(core.RuleSchemaRequiredComputed, fld.attrPos("Computed"),
+			"field `%s` is both `Required` and `Computed`", path)
+	}
+	return nil
+}
+
+func checkDefault(g Generator, path string, fld *Field) error {
+	mErr := &multierror.Error{}
+	for _, attr := range []string{"Default", "DefaultFunc"} {
+		if !fld.hasAttr(attr) {
+			continue
+		}
+		if fld.Required {
+			mErr = multierror.Append(mErr, g.ruleError(core.RuleSchemaDefault, fld.attrPos(attr),
+				"`%s` is set for required field `%s`", attr, path))
+		}
+		if fld.Computed {
+			mErr = multierror.Append(mErr, g.ruleError(core.RuleSchemaDefault, fld.attrPos(attr),
+				"`%s` is set for computed field `%s`", attr, path))
+		}
+	}
+	if fld.hasAttr("Default") && fld.hasAttr("DefaultFunc") {
+		mErr = multierror.Append(mErr, g.ruleError(core.RuleSchemaDefault, fld.attrPos("DefaultFunc"),
+			"both `Default` and `DefaultFunc` are set for field `%s`", path))
+	}
+	return mErr.ErrorOrNil()
+}
+
+func checkElem(g Generator, path string, fld *Field) error {
+	if fld.isCollection() && !fld.hasAttr("Elem") {
+		return g.ruleError(core.RuleSchemaElem, fld.attrPos("Type"),
+			"`Elem` must be set for `%s` field `%s`", fld.Type, path)
+	}
+	return nil
+}
+
+func checkMaxItems(g Generator, path string, fld *Field) error {
+	if fld.isCollection() {
+		return nil
+	}
+	mErr := &multierror.Error{}
+	for _, attr := range []string{"MaxItems", "MinItems"} {
+		if fld.hasAttr(attr) {
+			mErr = multierror.Append(mErr, g.ruleError(core.RuleSchemaMaxItems, fld.attrPos(attr),
+				"`%s` is set for `%s` field `%s`, only lists and sets are supported", attr, fld.Type, path))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+func checkComputedForceNew(g Generator, path string, fld *Field) error {
+	if fld.ForceNew && fld.computedOnly() {
+		return g.ruleError(core.RuleSchemaComputedForceNew, fld.attrPos("ForceNew"),
+			"`ForceNew` is set for computed-only field `%s`", path)
+	}
+	return nil
+}
+
+// ValidateUpdate checks that resource without `Update` has no updatable fields
+// and that resource with `Update` has at least one
+func (g Generator) ValidateUpdate() error {
+	if _, ok := g.Operations["Create"]; !ok {
+		return nil // data sources are not updatable
+	}
+	var updatable []string
+	for key, fld := range g.Schema {
+		if fld != nil && !fld.ForceNew && !fld.computedOnly() {
+			updatable = append(updatable, key)
+		}
+	}
+	sort.Strings(updatable)
+	_, hasUpdate := g.Operations["Update"]
+	if !hasUpdate && len(updatable) != 0 {
+		return g.ruleError(core.RuleResourceUpdate, g.Pos,
+			"no `Update` is defined for `%s`, `ForceNew` must be set for: %s",
+			g.Name, strings.Join(updatable, ", "))
+	}
+	if hasUpdate && len(updatable) == 0 {
+		return g.ruleError(core.RuleResourceUpdate, g.Pos,
+			"all fields of `%s` are `ForceNew` or computed-only, `Update` is superfluous", g.Name)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package generators
+
+import (
+	"path"
+	"strings"
+
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/set"
+)
+
+// sdkFeatures describes API differences of the SDK generations
+type sdkFeatures struct {
+	// ContextOperations is set if `CreateContext`, `ReadContext` and other context-aware fields are supported
+	ContextOperations bool
+	// Validators are schema fields with validation functions
+	Validators *set.StringSet
+	// PartialSetter is set if `d.SetPartial` is supported
+	PartialSetter bool
+}
+
+// sdkGenerations are API features by SDK generation
+var sdkGenerations = map[string]sdkFeatures{
+	core.SDKv1: {
+		Validators:    set.StringSetFromSlice([]string{"ValidateFunc"}),
+		PartialSetter: true,
+	},
+	core.SDKv2: {
+		ContextOperations: true,
+		Validators:        set.StringSetFromSlice([]string{"ValidateFunc", "ValidateDiagFunc"}),
+	},
+}
+
+// features returns API features of the SDK generation used by the generator, SDK v2 is used by default
+func (g Generator) features() sdkFeatures {
+	if f, ok := sdkGenerations[g.SDK.Generation]; ok {
+		return f
+	}
+	return sdkGenerations[core.SDKv2]
+}
+
+// isSupportedField checks if the resource or importer field is supported by the SDK generation, e.g. `CreateContext`
+func (g Generator) isSupportedField(key string) bool {
+	return g.features().ContextOperations || !strings.HasSuffix(key, "Context")
+}
+
+// validationImportPath returns the `helper/validation` package of the SDK used by the generator
+func (g Generator) validationImportPath() string {
+	sdkPath := g.SDK.Path
+	if sdkPath == "" {
+		sdkPath = core.SchemaImportPath
+	}
+	return path.Join(path.Dir(sdkPath), "validation")
+}
@@ -0,0 +1,271 @@
+package generators
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+)
+
+// sinkFuncs are functions writing their arguments to logs or error messages, by package path suffix
+var sinkFuncs = map[string][]string{
+	"log":    {"Print", "Printf", "Println", "Fatal", "Fatalf", "Fatalln", "Panic", "Panicf", "Panicln"},
+	"fmt":    {"Errorf"},
+	"fmterr": {"Errorf"},
+	"diag":   {"Errorf"},
+	"tflog":  {"Trace", "Debug", "Info", "Warn", "Error"},
+}
+
+// taint describes a value derived from a sensitive field
+type taint struct {
+	Key string
+	// Path contains steps of the flow from the source to the value
+	Path []string
+	// Fields are tainted fields of the struct value, nil for values tainted directly
+	Fields map[string]*taint
+}
+
+func (t *taint) step(name string) *taint {
+	if t == nil {
+		return nil
+	}
+	path := make([]string, len(t.Path), len(t.Path)+1)
+	copy(path, t.Path)
+	return &taint{Key: t.Key, Path: append(path, name), Fields: t.Fields}
+}
+
+// sensitiveKey checks if the value got by the key contains a sensitive field
+func (g Generator) sensitiveKey(key string) bool {
+	sensitive := false
+	walkSchema(g.Schema, "", func(path string, fld *Field) {
+		if !fld.Sensitive {
+			return
+		}
+		// the key is the sensitive field, its part or a block containing it
+		if path == key || strings.HasPrefix(key, path+".") || strings.HasPrefix(path, key+".") {
+			sensitive = true
+		}
+	})
+	return sensitive
+}
+
+// ValidateSensitiveLeaks checks that values of `Sensitive` fields got with `d.Get` or `d.GetOk`
+// are not passed to logs or error messages directly or as a part of a struct
+func (g Generator) ValidateSensitiveLeaks() error {
+	visited := map[*ast.FuncDecl]bool{}
+	mErr := &multierror.Error{}
+	for _, op := range sortedOperations(g.Operations) {
+		for _, fn := range g.reachableFns(g.Operations[op]) {
+			if visited[fn.Decl] {
+				continue
+			}
+			visited[fn.Decl] = true
+			mErr = multierror.Append(mErr, g.validateLeaks(fn))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+func sortedOperations(ops map[string]*ast.FuncDecl) []string {
+	names := make([]string, 0, len(ops))
+	for name := range ops {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateLeaks tracks tainted variables of the function in the source order
+func (g Generator) validateLeaks(fn dataFunc) error {
+	vars := map[string]*taint{}
+	mErr := &multierror.Error{}
+	ast.Inspect(fn.Decl.Body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range n.Lhs {
+				if len(n.Lhs) == len(n.Rhs) {
+					g.assignTaint(vars, lhs, g.exprTaint(n.Rhs[i], fn, vars))
+				} else if i == 0 { // `v, ok := d.GetOk("key")`
+					g.assignTaint(vars, lhs, g.exprTaint(n.Rhs[0], fn, vars))
+				}
+			}
+		case *ast.ValueSpec:
+			for i, name := range n.Names {
+				if i < len(n.Values) {
+					g.assignTaint(vars, name, g.exprTaint(n.Values[i], fn, vars))
+				}
+			}
+		case *ast.CallExpr:
+			sink := g.sinkName(n, fn)
+			if sink == "" {
+				return true
+			}
+			for _, arg := range n.Args {
+				t := g.exprTaint(arg, fn, vars)
+				if t == nil {
+					continue
+				}
+				if t.Fields != nil {
+					t = t.step("printed struct")
+				}
+				t = t.step(fmt.Sprintf("`%s`", sink))
+				mErr = multierror.Append(mErr, g.ruleError(core.RuleSensitiveLeak, n.Pos(),
+					"sensitive field `%s` leaks to `%s`: %s", t.Key, sink, strings.Join(t.Path, " -> ")))
+				break
+			}
+		}
+		return true
+	})
+	return mErr.ErrorOrNil()
+}
+
+// assignTaint marks variable or struct field as tainted, removing the taint if the new value is clean
+func (g Generator) assignTaint(vars map[string]*taint, lhs ast.Expr, t *taint) {
+	switch l := lhs.(type) {
+	case *ast.Ident:
+		if l.Name == "_" {
+			return
+		}
+		if t == nil {
+			delete(vars, l.Name)
+			return
+		}
+		vars[l.Name] = t.step(fmt.Sprintf("`%s`", l.Name))
+	case *ast.SelectorExpr:
+		x, ok := l.X.(*ast.Ident)
+		if !ok || t == nil {
+			return
+		}
+		current, ok := vars[x.Name]
+		if !ok {
+			current = &taint{Key: t.Key, Fields: map[string]*taint{}}
+			vars[x.Name] = current
+		}
+		if current.Fields == nil {
+			return // already tainted directly
+		}
+		field := t.step(fmt.Sprintf("`%s.%s`", x.Name, l.Sel.Name))
+		current.Fields[l.Sel.Name] = field
+		current.Key = field.Key
+		current.Path = field.Path
+	}
+}
+
+//nolint:cyclop
+func (g Generator) exprTaint(expr ast.Expr, fn dataFunc, vars map[string]*taint) *taint {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return vars[e.Name]
+	case *ast.ParenExpr:
+		return g.exprTaint(e.X, fn, vars)
+	case *ast.StarExpr:
+		return g.exprTaint(e.X, fn, vars)
+	case *ast.UnaryExpr:
+		return g.exprTaint(e.X, fn, vars)
+	case *ast.TypeAssertExpr:
+		return g.exprTaint(e.X, fn, vars)
+	case *ast.IndexExpr:
+		return g.exprTaint(e.X, fn, vars)
+	case *ast.BinaryExpr:
+		if t := g.exprTaint(e.X, fn, vars); t != nil {
+			return t
+		}
+		return g.exprTaint(e.Y, fn, vars)
+	case *ast.SelectorExpr:
+		t := g.exprTaint(e.X, fn, vars)
+		if t == nil || t.Fields == nil {
+			return t
+		}
+		return t.Fields[e.Sel.Name]
+	case *ast.CompositeLit:
+		return g.compositeTaint(e, fn, vars)
+	case *ast.CallExpr:
+		return g.callTaint(e, fn, vars)
+	}
+	return nil
+}
+
+// compositeTaint returns struct taint for the literal with tainted fields or direct taint for tainted collections
+func (g Generator) compositeTaint(lit *ast.CompositeLit, fn dataFunc, vars map[string]*taint) *taint {
+	var result *taint
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			if t := g.exprTaint(elt, fn, vars); t != nil {
+				return t
+			}
+			continue
+		}
+		t := g.exprTaint(kv.Value, fn, vars)
+		if t == nil {
+			continue
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok { // map literal
+			return t
+		}
+		field := t.step(fmt.Sprintf("field `%s`", key.Name))
+		if result == nil {
+			result = &taint{Key: field.Key, Path: field.Path, Fields: map[string]*taint{}}
+		}
+		result.Fields[key.Name] = field
+	}
+	return result
+}
+
+// callTaint returns taint for `d.Get` of sensitive fields and string formatting of tainted values
+func (g Generator) callTaint(call *ast.CallExpr, fn dataFunc, vars map[string]*taint) *taint {
+	if isDataMethod(call.Fun, fn.DName, "Get", "GetOk") && len(call.Args) == 1 {
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return nil
+		}
+		key, _ := core.UnwrapString(lit)
+		if !g.sensitiveKey(key) {
+			return nil
+		}
+		method := call.Fun.(*ast.SelectorExpr).Sel.Name
+		source := fmt.Sprintf("`%s.%s(%s)` (line %d)", fn.DName, method, lit.Value, g.position(call.Pos()).Line)
+		return &taint{Key: key, Path: []string{source}}
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	if x, ok := sel.X.(*ast.Ident); !ok || g.absoluteImport(x.Name, sel, fn.Pkg) != "fmt" || sel.Sel.Name != "Sprintf" {
+		return nil
+	}
+	for _, arg := range call.Args {
+		if t := g.exprTaint(arg, fn, vars); t != nil {
+			return t.step("`fmt.Sprintf`")
+		}
+	}
+	return nil
+}
+
+// sinkName returns name of the sink function called, empty if the call is not a sink
+func (g Generator) sinkName(call *ast.CallExpr, fn dataFunc) string {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	x, ok := sel.X.(*ast.Ident)
+	if !ok || x.Obj != nil {
+		return ""
+	}
+	path := g.absoluteImport(x.Name, sel, fn.Pkg)
+	if path == "" {
+		return ""
+	}
+	pkgName := path[strings.LastIndex(path, "/")+1:]
+	for _, name := range sinkFuncs[pkgName] {
+		if name == sel.Sel.Name {
+			return fmt.Sprintf("%s.%s", pkgName, name)
+		}
+	}
+	return ""
+}
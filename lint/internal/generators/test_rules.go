@@ -0,0 +1,116 @@
+package generators
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+)
+
+// idAttribute exists in the state of every resource
+const idAttribute = "id"
+
+// ValidateTestAttributes checks that attribute keys used in acceptance test checks
+// and `ImportStateVerifyIgnore` lists exist in the schema
+func (g Generator) ValidateTestAttributes() error {
+	if g.Resource.TypeName == "" || g.Schema == nil {
+		return nil
+	}
+	mErr := &multierror.Error{}
+	for _, ref := range g.Project.AttrChecks[g.Resource] {
+		mErr = multierror.Append(mErr, g.validateTestAttribute(ref, false))
+	}
+	// `ImportStateVerifyIgnore` values are prefixes, so whole collections can be ignored
+	for _, step := range g.importSteps() {
+		for _, ref := range step.Ignore {
+			mErr = multierror.Append(mErr, g.validateTestAttribute(ref, true))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+func (g Generator) validateTestAttribute(ref core.Reference, prefix bool) error {
+	if ref.Key == idAttribute {
+		return nil
+	}
+	if err := validateAttrPath(g.Schema, ref.Key, prefix); err != nil {
+		return g.ruleError(core.RuleTestAttributes, ref.Pos,
+			"invalid attribute `%s` of `%s`: %s", ref.Key, g.Resource, err)
+	}
+	return nil
+}
+
+// isElemIndex checks if the path part is an index of a list or a set element,
+// `*` is used by `TestCheckTypeSetElem*` checks
+func isElemIndex(part string) bool {
+	if part == "*" {
+		return true
+	}
+	_, err := strconv.Atoi(part)
+	return err == nil
+}
+
+// validateAttrPath checks flatmap attribute path used in the state, e.g. `block.#`, `block.0.field` or `tags.%`,
+// collection itself is a valid path only if the path is used as a prefix
+func validateAttrPath(schema map[string]*Field, path string, prefix bool) error {
+	parts := strings.Split(path, ".")
+	fld, ok := schema[parts[0]]
+	if !ok || fld == nil {
+		return fmt.Errorf("unknown attribute `%s`", parts[0])
+	}
+	rest := parts[1:]
+	if len(rest) == 0 {
+		switch {
+		case prefix:
+			return nil
+		case fld.isCollection():
+			return fmt.Errorf("`%s` is a collection, use `%s.#` or an element path", parts[0], parts[0])
+		case fld.Type == "TypeMap":
+			return fmt.Errorf("`%s` is a map, use `%s.%%` or a key path", parts[0], parts[0])
+		}
+		return nil
+	}
+	switch fld.Type {
+	case "TypeMap":
+		if rest[0] == "%" && len(rest) == 1 {
+			return nil
+		}
+		if rest[0] == "#" {
+			return fmt.Errorf("map size is stored as `%s.%%`", parts[0])
+		}
+		return nil // any map key
+	case "TypeList", "TypeSet":
+		if rest[0] == "#" && len(rest) == 1 {
+			return nil
+		}
+		if rest[0] == "%" {
+			return fmt.Errorf("list size is stored as `%s.#`", parts[0])
+		}
+		if !isElemIndex(rest[0]) {
+			return fmt.Errorf("`%s` is a collection, element index expected instead of `%s`", parts[0], rest[0])
+		}
+		if len(rest) == 1 {
+			// `block.*` addresses the whole element in `TestCheckTypeSetElemNestedAttrs`
+			if fld.Schema != nil && !prefix && rest[0] != "*" {
+				return fmt.Errorf("`%s` elements are blocks, nested attribute expected", parts[0])
+			}
+			return nil
+		}
+		if fld.Schema == nil {
+			if fld.Elem == nil {
+				return nil // unknown element type
+			}
+			return fmt.Errorf("`%s` elements have no nested attributes", parts[0])
+		}
+		if err := validateAttrPath(fld.Schema, strings.Join(rest[1:], "."), prefix); err != nil {
+			return fmt.Errorf("in `%s`: %w", parts[0], err)
+		}
+		return nil
+	}
+	if fld.Type == "" {
+		return nil // type is unknown
+	}
+	return fmt.Errorf("`%s` is a primitive and has no nested attributes", parts[0])
+}
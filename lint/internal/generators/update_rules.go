@@ -0,0 +1,56 @@
+package generators
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+)
+
+// topKey returns top-level field name of the key, e.g. `block` for `block.0.field`
+func topKey(key string) string {
+	return strings.SplitN(key, ".", 2)[0]
+}
+
+// ValidateUpdateCoverage checks that every updatable field is checked with `HasChange` or read in `Update`
+// and no `ForceNew` field is checked with `HasChange` there
+func (g Generator) ValidateUpdateCoverage() error {
+	update := g.Operations["Update"]
+	if update == nil {
+		return nil
+	}
+	// `Update` usually ends with `Read` call, values got there are not used for update
+	fns := g.reachableFns(update, g.Operations["Read"])
+	changes := dataKeys(fns, "HasChange", "HasChanges")
+	used := map[string]bool{}
+	for key := range dataKeys(fns, "HasChange", "HasChanges", "GetChange", "Get", "GetOk", "GetOkExists") {
+		used[topKey(key)] = true
+	}
+
+	mErr := &multierror.Error{}
+	for _, key := range sortedKeys(g.Schema) {
+		fld := g.Schema[key]
+		if fld == nil || fld.ForceNew || fld.Computed || used[key] {
+			continue
+		}
+		mErr = multierror.Append(mErr, g.ruleError(core.RuleUpdateCoverage, fld.Pos,
+			"field `%s` is neither checked with `HasChange` nor read in `Update`, its changes produce a perpetual diff", key))
+	}
+	changed := make([]string, 0, len(changes))
+	for key := range changes {
+		changed = append(changed, key)
+	}
+	sort.Strings(changed)
+	for _, key := range changed {
+		fld := g.Schema[topKey(key)]
+		if fld == nil || !fld.ForceNew {
+			continue
+		}
+		for _, lit := range changes[key] {
+			mErr = multierror.Append(mErr, g.ruleError(core.RuleUpdateCoverage, lit.Pos(),
+				"`HasChange` is called for `ForceNew` field `%s` in `Update`, the check is dead code", key))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
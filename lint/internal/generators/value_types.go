@@ -0,0 +1,216 @@
+package generators
+
+import (
+	"go/ast"
+	"go/token"
+	"log"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+)
+
+// knownValidators maps `helper/validation` functions to types of validated value
+var knownValidators = map[string]string{
+	"StringInSlice":             "string",
+	"StringNotInSlice":          "string",
+	"StringLenBetween":          "string",
+	"StringMatch":               "string",
+	"StringDoesNotMatch":        "string",
+	"StringIsEmpty":             "string",
+	"StringIsNotEmpty":          "string",
+	"StringIsWhiteSpace":        "string",
+	"StringIsNotWhiteSpace":     "string",
+	"StringDoesNotContainAny":   "string",
+	"StringIsBase64":            "string",
+	"StringIsJSON":              "string",
+	"StringIsValidRegExp":       "string",
+	"IsCIDR":                    "string",
+	"IsCIDRNetwork":             "string",
+	"IsIPAddress":               "string",
+	"IsIPv4Address":             "string",
+	"IsIPv6Address":             "string",
+	"IsIPv4Range":               "string",
+	"IsMACAddress":              "string",
+	"IsURLWithHTTPS":            "string",
+	"IsURLWithHTTPorHTTPS":      "string",
+	"IsURLWithScheme":           "string",
+	"IsUUID":                    "string",
+	"IsRFC3339Time":             "string",
+	"IsDayOfTheWeek":            "string",
+	"IsMonth":                   "string",
+	"ValidateJsonString":        "string",
+	"ValidateRegexp":            "string",
+	"ValidateRFC3339TimeString": "string",
+	"IsPortNumber":              "int",
+	"IsPortNumberOrZero":        "int",
+	"IntBetween":                "int",
+	"IntAtLeast":                "int",
+	"IntAtMost":                 "int",
+	"IntInSlice":                "int",
+	"IntNotInSlice":             "int",
+	"IntDivisibleBy":            "int",
+	"FloatBetween":              "float",
+	"FloatAtLeast":              "float",
+	"FloatAtMost":               "float",
+	"MapKeyLenBetween":          "map",
+	"MapValueLenBetween":        "map",
+	"MapKeyMatch":               "map",
+	"MapValueMatch":             "map",
+	"ListOfUniqueStrings":       "array",
+}
+
+// validatorCombinators are validators applying all their arguments to the same value
+var validatorCombinators = map[string]bool{
+	"All":        true,
+	"Any":        true,
+	"ToDiagFunc": true,
+}
+
+// ValidateValueTypes checks that `Default` values and validators match field types
+func (g Generator) ValidateValueTypes() error {
+	mErr := &multierror.Error{}
+	walkSchema(g.Schema, "", func(path string, fld *Field) {
+		if !g.ownPos(fld.Pos) {
+			return // field declared in other package, types will be resolved incorrectly
+		}
+		mErr = multierror.Append(mErr, g.checkDefaultType(path, fld))
+		mErr = multierror.Append(mErr, g.checkValidatorTypes(path, fld))
+		if fld.Elem != nil {
+			mErr = multierror.Append(mErr, g.checkValidatorTypes(path+".*", fld.Elem))
+		}
+	})
+	return mErr.ErrorOrNil()
+}
+
+// ownPos checks if the position is inside the generator package
+func (g Generator) ownPos(pos token.Pos) bool {
+	file := g.FSet.File(pos)
+	if file == nil {
+		return false
+	}
+	for _, f := range g.Pkg.Syntax {
+		if g.FSet.File(f.Package) == file {
+			return true
+		}
+	}
+	return false
+}
+
+func (g Generator) checkDefaultType(path string, fld *Field) error {
+	if fld.Default == nil {
+		return nil
+	}
+	if ident, ok := fld.Default.(*ast.Ident); ok && ident.Name == "nil" {
+		return nil
+	}
+	expected := typeMapping[fld.Type]
+	if expected == "" {
+		return nil
+	}
+	if fld.isCollection() {
+		return g.ruleError(core.RuleSchemaValueTypes, fld.attrPos("Default"),
+			"`Default` is not supported for `%s` field `%s`", fld.Type, path)
+	}
+	typ, err := g.getExpType(fld.Default, g.Pkg)
+	if err != nil {
+		log.Printf("can't determine `Default` type of `%s`: %s", path, err)
+		return nil
+	}
+	if _, ok := typ.(*core.StubType); ok || typ == nil {
+		return nil // too complex expression
+	}
+	if !g.extendedMatch(typ, expected) {
+		return g.ruleError(core.RuleSchemaValueTypes, fld.attrPos("Default"),
+			"`Default` of field `%s` has type `%s`, expected `%s`", path, typ.String(), expected)
+	}
+	return nil
+}
+
+func (g Generator) checkValidatorTypes(path string, fld *Field) error {
+	expected := typeMapping[fld.Type]
+	if expected == "" {
+		return nil
+	}
+	mErr := &multierror.Error{}
+	for _, v := range fld.Validators {
+		for _, name := range g.validatorNames(v) {
+			typ, ok := g.validatorType(name)
+			if !ok || typ == expected {
+				continue
+			}
+			mErr = multierror.Append(mErr, g.ruleError(core.RuleSchemaValueTypes, v.Pos(),
+				"validator `%s` of field `%s` validates `%s` values, but field type is `%s`",
+				name, path, typ, expected))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// validatorType finds type of value validated by the validator with the given full name
+func (g Generator) validatorType(name string) (string, bool) {
+	if typ, ok := g.Config.Validators[name]; ok {
+		return typ, true
+	}
+	pkg, fn := splitFullName(name)
+	if pkg != g.validationImportPath() {
+		return "", false
+	}
+	typ, ok := knownValidators[fn]
+	return typ, ok
+}
+
+// splitFullName splits `<import path>.<name>` into the path and the name
+func splitFullName(name string) (string, string) {
+	for i := len(name) - 1; i >= 0; i-- {
+		if name[i] == '.' {
+			return name[:i], name[i+1:]
+		}
+	}
+	return "", name
+}
+
+// validatorNames returns full names of validator functions used in the expression
+func (g Generator) validatorNames(expr ast.Expr) []string {
+	switch e := expr.(type) {
+	case *ast.CallExpr:
+		name := g.funcFullName(e.Fun)
+		if _, fn := splitFullName(name); validatorCombinators[fn] {
+			var names []string
+			for _, arg := range e.Args {
+				names = append(names, g.validatorNames(arg)...)
+			}
+			return names
+		}
+		if name == "" {
+			return nil
+		}
+		return []string{name}
+	case *ast.SelectorExpr, *ast.Ident:
+		if name := g.funcFullName(e); name != "" {
+			return []string{name}
+		}
+	}
+	return nil
+}
+
+// funcFullName returns `<import path>.<name>` of the function used in expression
+func (g Generator) funcFullName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if _, ok := g.scopeCache[g.Pkg.ID].FuncDecls[e.Name]; !ok {
+			return "" // builtin or not a function
+		}
+		return core.MethodName(g.Pkg.ID, e.Name)
+	case *ast.SelectorExpr:
+		x, ok := e.X.(*ast.Ident)
+		if !ok || x.Obj != nil {
+			return "" // method call
+		}
+		path := g.absoluteImport(x.Name, e, g.Pkg)
+		if path == "" {
+			return ""
+		}
+		return core.MethodName(path, e.Sel.Name)
+	}
+	return ""
+}
@@ -18,6 +18,7 @@ var (
 	tokenToType = map[token.Token]string{
 		token.STRING: "string",
 		token.INT:    "int",
+		token.FLOAT:  "float",
 		token.MAP:    "map",
 	}
 	typeMapping = map[string]string{
@@ -107,39 +108,8 @@ func (g Generator) getCachedScope(pkg *packages.Package) (*core.Scope, error) {
 	return scope, nil
 }
 
-func getPkgName(pkg *packages.Package) string {
-	for _, file := range pkg.Syntax {
-		return file.Name.Name
-	}
-	return ""
-}
-
 func (g Generator) absoluteImport(pkgName string, expr ast.Expr, pkg *packages.Package) string {
-	if pkgName == pkg.ID {
-		return pkg.ID
-	}
-	srcFile := g.FSet.File(expr.Pos())
-	for _, fl := range pkg.Syntax {
-		flPos := g.FSet.File(fl.Package)
-		if flPos.Name() != srcFile.Name() { // we found file with the expression
-			continue
-		}
-		for _, i := range fl.Imports {
-			var alias string
-			path := strings.Trim(i.Path.Value, `"`)
-			if i.Name != nil {
-				alias = i.Name.Name
-			} else {
-				importedPackageName := getPkgName(pkg.Imports[path])
-				alias = importedPackageName
-			}
-			if pkgName == alias {
-				return path
-			}
-		}
-	}
-	fallback := pkg.Imports[pkgName].ID // use package import as a fallback
-	return fallback
+	return core.AbsoluteImport(g.FSet, pkgName, expr, pkg)
 }
 
 func (g Generator) packageScope(pkg *packages.Package) (s *core.Scope, err error) {
@@ -175,6 +145,7 @@ func (g Generator) packageScope(pkg *packages.Package) (s *core.Scope, err error
 		FuncTypes:   map[string]*core.FuncType{},
 		StructDecls: structDeclarations,
 		StructTypes: map[string]*core.StructType{},
+		Helpers:     map[string]*core.HelperSummary{},
 	}, nil
 }
 
@@ -296,7 +267,7 @@ func (g Generator) getUnaryExprType(u *ast.UnaryExpr, pkg *packages.Package) (co
 		}
 		return aType.ItemType, nil
 	}
-	if u.Op == token.AND {
+	if u.Op == token.AND || u.Op == token.SUB || u.Op == token.ADD {
 		return g.getExpType(u.X, pkg)
 	}
 	return nil, fmt.Errorf("unsupported unary operation")
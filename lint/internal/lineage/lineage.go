@@ -0,0 +1,178 @@
+// Package lineage contains report of the response struct fields feeding resource attributes
+package lineage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/generators"
+)
+
+// Format is the report output format
+type Format string
+
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+)
+
+// Formats are all supported report formats
+var Formats = []Format{FormatText, FormatJSON}
+
+// Field is a struct field feeding the attribute
+type Field struct {
+	// Struct is the struct name with the package name, e.g. `servers.Server`
+	Struct   string `json:"struct"`
+	Field    string `json:"field"`
+	Position string `json:"position"`
+}
+
+func (f Field) String() string {
+	return f.Struct + "." + f.Field
+}
+
+// Resource contains lineage of all top-level attributes of the resource
+type Resource struct {
+	TypeName   string `json:"type_name"`
+	DataSource bool   `json:"data_source,omitempty"`
+	// SDK is the SDK generation used by the resource, e.g. `v2`
+	SDK string `json:"sdk"`
+	// Attributes are fields feeding the attribute by attribute key, attributes without known sources have no fields
+	Attributes map[string][]Field `json:"attributes"`
+	// Unexposed are exported fields of the used structs not feeding any attribute, by struct name
+	Unexposed map[string][]string `json:"unexposed,omitempty"`
+}
+
+// Report is the lineage of all provider resources
+type Report struct {
+	Resources []*Resource `json:"resources"`
+}
+
+// shortStruct returns struct name with the package name instead of the import path
+func shortStruct(name string) string {
+	dot := strings.LastIndex(name, ".")
+	if dot < 0 {
+		return name
+	}
+	return path.Base(name[:dot]) + name[dot:]
+}
+
+// FromGenerator creates lineage of the single resource
+func FromGenerator(gen *generators.Generator) *Resource {
+	res := &Resource{
+		TypeName:   gen.Resource.TypeName,
+		DataSource: gen.Resource.DataSource,
+		SDK:        gen.SDK.Generation,
+		Attributes: map[string][]Field{},
+		Unexposed:  map[string][]string{},
+	}
+	if res.TypeName == "" {
+		res.TypeName = gen.Name
+	}
+	lineage := gen.Lineage()
+	used := map[string]bool{}
+	for key, fld := range gen.Schema {
+		if fld == nil {
+			continue
+		}
+		fields := []Field{}
+		for _, src := range lineage.Sources[key] {
+			pos := gen.FSet.Position(src.Pos)
+			fields = append(fields, Field{
+				Struct:   shortStruct(src.Struct),
+				Field:    src.Field,
+				Position: fmt.Sprintf("%s:%d", filepath.Base(pos.Filename), pos.Line),
+			})
+			used[src.Struct+"."+src.Field] = true
+		}
+		res.Attributes[key] = fields
+	}
+	for name, fields := range lineage.Fields {
+		for _, fld := range fields {
+			if !used[name+"."+fld] {
+				res.Unexposed[shortStruct(name)] = append(res.Unexposed[shortStruct(name)], fld)
+			}
+		}
+	}
+	return res
+}
+
+// FromGenerators creates lineage report of the resources, generators not registered in the provider
+// are reported by the function name
+func FromGenerators(gens []*generators.Generator) *Report {
+	report := &Report{}
+	for _, gen := range gens {
+		if gen.Schema == nil {
+			continue
+		}
+		report.Resources = append(report.Resources, FromGenerator(gen))
+	}
+	sort.Slice(report.Resources, func(i, j int) bool {
+		a, b := report.Resources[i], report.Resources[j]
+		if a.DataSource != b.DataSource {
+			return !a.DataSource
+		}
+		return a.TypeName < b.TypeName
+	})
+	return report
+}
+
+// Write writes the report in the given format
+func (r *Report) Write(w io.Writer, format Format) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(r); err != nil {
+			return fmt.Errorf("error serializing lineage: %w", err)
+		}
+		return nil
+	case FormatText:
+		return r.writeText(w)
+	}
+	return fmt.Errorf("unknown lineage format `%s`", format)
+}
+
+func (r *Report) writeText(w io.Writer) error {
+	b := &strings.Builder{}
+	for i, res := range r.Resources {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		kind := "resource"
+		if res.DataSource {
+			kind = "data source"
+		}
+		_, _ = fmt.Fprintf(b, "%s (%s, SDK %s)\n", res.TypeName, kind, res.SDK)
+		keys := make([]string, 0, len(res.Attributes))
+		for key := range res.Attributes {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fields := res.Attributes[key]
+			if len(fields) == 0 {
+				_, _ = fmt.Fprintf(b, "  %s <- ?\n", key)
+				continue
+			}
+			for _, fld := range fields {
+				_, _ = fmt.Fprintf(b, "  %s <- %s (%s)\n", key, fld, fld.Position)
+			}
+		}
+		structs := make([]string, 0, len(res.Unexposed))
+		for name := range res.Unexposed {
+			structs = append(structs, name)
+		}
+		sort.Strings(structs)
+		for _, name := range structs {
+			_, _ = fmt.Fprintf(b, "  unexposed %s: %s\n", name, strings.Join(res.Unexposed[name], ", "))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,211 @@
+package migrate
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// edit replaces source bytes between the offsets with the text
+type edit struct {
+	Start int
+	End   int
+	Text  string
+}
+
+// Migration contains rewrites of files and legacy usages which can't be rewritten
+type Migration struct {
+	Notes []Note
+
+	edits map[string][]edit
+	// imports are packages to be added to the file, by the import path
+	imports map[string]map[string]string
+}
+
+// Filenames returns sorted names of files changed by the migration
+func (m *Migration) Filenames() []string {
+	result := make([]string, 0, len(m.edits))
+	for name := range m.edits {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Apply returns the original and the migrated source of the file
+func (m *Migration) Apply(filename string) ([]byte, []byte, error) {
+	src, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading %s: %w", filename, err)
+	}
+	edits := append([]edit{}, m.edits[filename]...)
+	sort.SliceStable(edits, func(i, j int) bool {
+		if edits[i].Start != edits[j].Start {
+			return edits[i].Start > edits[j].Start
+		}
+		return edits[i].End > edits[j].End
+	})
+	result := append([]byte{}, src...)
+	limit := len(src)
+	for i, e := range edits {
+		if i > 0 && e == edits[i-1] {
+			continue // the same rewrite of the shared declaration
+		}
+		if e.End > limit || e.Start > e.End {
+			return nil, nil, fmt.Errorf("%s: overlapping rewrites at offset %d", filename, e.Start)
+		}
+		result = append(result[:e.Start], append([]byte(e.Text), result[e.End:]...)...)
+		limit = e.Start // insertions at the same offset are allowed, replacements of following bytes are not
+	}
+	result, err = addImports(filename, result, m.imports[filename])
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err = removeUnusedImports(filename, src, result)
+	if err != nil {
+		return nil, nil, err
+	}
+	formatted, err := format.Source(result)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: migrated source is broken: %w", filename, err)
+	}
+	return src, formatted, nil
+}
+
+// addImports adds imports to the first import declaration of the source, standard packages are added
+// to the first group and other packages to the last one
+func addImports(filename string, src []byte, imports map[string]string) ([]byte, error) {
+	if len(imports) == 0 {
+		return src, nil
+	}
+	fSet := token.NewFileSet()
+	file, err := parser.ParseFile(fSet, filename, src, parser.ImportsOnly)
+	if err != nil {
+		return nil, fmt.Errorf("%s: migrated source is broken: %w", filename, err)
+	}
+	paths := make([]string, 0, len(imports))
+	for importPath := range imports {
+		paths = append(paths, importPath)
+	}
+	sort.Strings(paths)
+	std, other := &bytes.Buffer{}, &bytes.Buffer{}
+	for _, importPath := range paths {
+		lines := other
+		if !strings.Contains(strings.Split(importPath, "/")[0], ".") {
+			lines = std
+		}
+		if name := imports[importPath]; name != path.Base(importPath) {
+			_, _ = fmt.Fprintf(lines, "\t%s %s\n", name, strconv.Quote(importPath))
+			continue
+		}
+		_, _ = fmt.Fprintf(lines, "\t%s\n", strconv.Quote(importPath))
+	}
+
+	var decl *ast.GenDecl
+	for _, d := range file.Decls {
+		if gen, ok := d.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			decl = gen
+			break
+		}
+	}
+	if decl == nil {
+		return insertAt(src, fSet.Position(file.Name.End()).Offset, "\n\nimport (\n"+std.String()+other.String()+")"), nil
+	}
+	if !decl.Lparen.IsValid() { // single import without parentheses
+		start, end := fSet.Position(decl.Pos()).Offset, fSet.Position(decl.End()).Offset
+		spec := strings.TrimSpace(string(src[start+len("import") : end]))
+		text := "import (\n" + std.String() + other.String() + "\t" + spec + "\n)"
+		return append(append(append([]byte{}, src[:start]...), text...), src[end:]...), nil
+	}
+	// the last group is changed first not to shift the opening parenthesis
+	src = insertAt(src, fSet.Position(decl.Rparen).Offset, other.String())
+	if std.Len() == 0 {
+		return src, nil
+	}
+	return insertAt(src, fSet.Position(decl.Lparen).Offset+1, "\n"+strings.TrimSuffix(std.String(), "\n")), nil
+}
+
+// usedPackages returns names used as selector qualifiers not declared in the file, i.e. package names
+func usedPackages(file *ast.File) map[string]bool {
+	used := map[string]bool{}
+	ast.Inspect(file, func(node ast.Node) bool {
+		if sel, ok := node.(*ast.SelectorExpr); ok {
+			if x, ok := sel.X.(*ast.Ident); ok && x.Obj == nil {
+				used[x.Name] = true
+			}
+		}
+		return true
+	})
+	return used
+}
+
+// removeUnusedImports removes imports used in the original source, but not in the migrated one,
+// e.g. `fmt` after all `fmt.Errorf` calls are rewritten. Imports without a name are matched by the last
+// path element, imports which can't be matched this way are kept
+func removeUnusedImports(filename string, original, src []byte) ([]byte, error) {
+	origFile, err := parser.ParseFile(token.NewFileSet(), filename, original, 0)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", filename, err)
+	}
+	fSet := token.NewFileSet()
+	file, err := parser.ParseFile(fSet, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("%s: migrated source is broken: %w", filename, err)
+	}
+	wasUsed, used := usedPackages(origFile), usedPackages(file)
+	removed := false
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path.Base(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if !wasUsed[name] || used[name] {
+			continue
+		}
+		if spec.Name != nil {
+			removed = astutil.DeleteNamedImport(fSet, file, name, importPath) || removed
+		} else {
+			removed = astutil.DeleteImport(fSet, file, importPath) || removed
+		}
+	}
+	if !removed {
+		return src, nil
+	}
+	buf := &bytes.Buffer{}
+	if err := format.Node(buf, fSet, file); err != nil {
+		return nil, fmt.Errorf("%s: migrated source is broken: %w", filename, err)
+	}
+	return buf.Bytes(), nil
+}
+
+func insertAt(src []byte, offset int, text string) []byte {
+	result := append([]byte{}, src[:offset]...)
+	result = append(result, text...)
+	return append(result, src[offset:]...)
+}
+
+// Diff returns unified diff of the file migration, paths are prefixed with `a/` and `b/`
+func Diff(name string, before, after []byte) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(before)),
+		B:        difflib.SplitLines(string(after)),
+		FromFile: "a/" + name,
+		ToFile:   "b/" + name,
+		Context:  3,
+	})
+}
@@ -0,0 +1,655 @@
+// Package migrate rewrites legacy SDK v2 APIs of resources: CRUD functions without context,
+// `ValidateFunc` validators and `resource.Retry` calls
+package migrate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/generators"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/parser"
+	"golang.org/x/tools/go/packages"
+)
+
+// legacyOperations maps resource fields with CRUD functions without context to their replacements
+var legacyOperations = map[string]string{
+	"Create": "CreateContext",
+	"Read":   "ReadContext",
+	"Update": "UpdateContext",
+	"Delete": "DeleteContext",
+}
+
+// Options configures the rewrites
+type Options struct {
+	// Errorf is the function replacing `fmt.Errorf` in returned errors, `<import path>.<name>`,
+	// e.g. `.../common/fmterr.Errorf`, SDK `diag.Errorf` is used by default
+	Errorf string
+}
+
+// Note is a legacy API usage which is not rewritten
+type Note struct {
+	Pos     token.Position
+	Message string
+}
+
+func (n Note) String() string {
+	return fmt.Sprintf("%s - %s", n.Pos.String(), n.Message)
+}
+
+// operation is a CRUD function referenced by the resource field
+type operation struct {
+	Field *ast.KeyValueExpr
+	Decl  *ast.FuncDecl
+}
+
+// resource is a resource with legacy CRUD functions
+type resource struct {
+	Gen        *generators.Generator
+	Lit        *ast.CompositeLit
+	Operations []operation
+	Refused    string
+}
+
+type planner struct {
+	opts      Options
+	fSet      *token.FileSet
+	migration *Migration
+	resources []*resource
+	// funcs are functions to be migrated by name, `<package>.<function>`
+	funcs map[string]*ast.FuncDecl
+	// migrated are CRUD functions with rewritten signatures, functions may be shared by resources
+	migrated map[*ast.FuncDecl]bool
+	// tests are `_test.go` files of test variants by the tested package path
+	tests map[string][]*ast.File
+	noted map[token.Pos]bool
+}
+
+// Plan finds legacy API usages of SDK v2 resources and prepares rewrites, usages which can't be
+// rewritten safely are reported as notes
+func Plan(gens []*generators.Generator, opts Options) (*Migration, error) {
+	if len(gens) == 0 {
+		return &Migration{edits: map[string][]edit{}}, nil
+	}
+	p := &planner{
+		opts:      opts,
+		fSet:      gens[0].FSet,
+		migration: &Migration{edits: map[string][]edit{}, imports: map[string]map[string]string{}},
+		funcs:     map[string]*ast.FuncDecl{},
+		migrated:  map[*ast.FuncDecl]bool{},
+		tests:     testFiles(gens[0].Project.Packages, gens[0].FSet),
+		noted:     map[token.Pos]bool{},
+	}
+	sort.Slice(gens, func(i, j int) bool {
+		return p.position(gens[i].Pos).String() < p.position(gens[j].Pos).String()
+	})
+	for _, gen := range gens {
+		if gen.SDK.Generation != core.SDKv2 {
+			p.note(gen.Pos, "`%s` uses SDK %s, move it to SDK v2 before migrating legacy APIs", gen.Name, gen.SDK.Generation)
+			continue
+		}
+		if res := p.legacyResource(gen); res != nil {
+			p.resources = append(p.resources, res)
+		}
+		if err := p.migrateValidators(gen, gen.Schema); err != nil {
+			return nil, err
+		}
+	}
+	p.refuseUnsafe()
+	for _, res := range p.resources {
+		if res.Refused != "" {
+			p.note(res.Gen.Pos, "`%s` CRUD functions are not migrated: %s", res.Gen.Name, res.Refused)
+			continue
+		}
+		if err := p.migrateResource(res); err != nil {
+			return nil, err
+		}
+	}
+	for _, gen := range gens {
+		p.noteLegacyCalls(gen)
+	}
+	sort.Slice(p.migration.Notes, func(i, j int) bool {
+		a, b := p.migration.Notes[i].Pos, p.migration.Notes[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Offset < b.Offset
+	})
+	return p.migration, nil
+}
+
+// testFiles collects `_test.go` files of test variants, other files of the variants are copies of the package files
+func testFiles(pkgs []*packages.Package, fSet *token.FileSet) map[string][]*ast.File {
+	result := map[string][]*ast.File{}
+	for _, pkg := range pkgs {
+		if !parser.IsTestVariant(pkg) {
+			continue
+		}
+		pkgPath := strings.TrimSuffix(pkg.PkgPath, "_test")
+		for _, file := range pkg.Syntax {
+			if strings.HasSuffix(fSet.Position(file.Package).Filename, "_test.go") {
+				result[pkgPath] = append(result[pkgPath], file)
+			}
+		}
+	}
+	return result
+}
+
+func (p *planner) position(pos token.Pos) token.Position {
+	return generators.Position(p.fSet, pos)
+}
+
+func (p *planner) note(pos token.Pos, format string, args ...interface{}) {
+	if p.noted[pos] {
+		return
+	}
+	p.noted[pos] = true
+	p.migration.Notes = append(p.migration.Notes, Note{Pos: p.position(pos), Message: fmt.Sprintf(format, args...)})
+}
+
+// replace adds the edit replacing the source between the positions
+func (p *planner) replace(start, end token.Pos, text string) {
+	s, e := p.fSet.Position(start), p.fSet.Position(end)
+	p.migration.edits[s.Filename] = append(p.migration.edits[s.Filename], edit{Start: s.Offset, End: e.Offset, Text: text})
+}
+
+func (p *planner) insert(pos token.Pos, text string) {
+	p.replace(pos, pos, text)
+}
+
+// fileOf returns the file of the package containing the position
+func fileOf(pkg *packages.Package, pos token.Pos) *ast.File {
+	for _, file := range pkg.Syntax {
+		if file.Pos() <= pos && pos <= file.End() {
+			return file
+		}
+	}
+	return nil
+}
+
+// importName returns the name the package is imported with in the file, the import is added if it's missing,
+// error is returned if the name is taken by another import
+func (p *planner) importName(file *ast.File, importPath string) (string, error) {
+	name := path.Base(importPath)
+	if strings.HasPrefix(name, "v") && strings.Trim(name[1:], "0123456789") == "" {
+		name = path.Base(path.Dir(importPath)) // major version suffix
+	}
+	for _, imp := range file.Imports {
+		val, _ := core.UnwrapString(imp.Path)
+		if val == importPath {
+			if imp.Name != nil {
+				return imp.Name.Name, nil
+			}
+			return name, nil
+		}
+		if (imp.Name != nil && imp.Name.Name == name) || (imp.Name == nil && path.Base(val) == name) {
+			return "", fmt.Errorf("`%s` is imported as `%s`", val, name)
+		}
+	}
+	if obj := file.Scope.Lookup(name); obj != nil {
+		return "", fmt.Errorf("`%s` is declared in the file", name)
+	}
+	filename := p.fSet.Position(file.Package).Filename
+	if p.migration.imports[filename] == nil {
+		p.migration.imports[filename] = map[string]string{}
+	}
+	p.migration.imports[filename][importPath] = name
+	return name, nil
+}
+
+// sdkPath returns the import path of the SDK package next to the `helper/schema`, e.g. `diag` or `helper/resource`
+func sdkPath(gen *generators.Generator, rel string) string {
+	root := path.Dir(path.Dir(gen.SDK.Path)) // strip `helper/schema`
+	return path.Join(root, rel)
+}
+
+// findLiteral finds the resource literal of the generator
+func findLiteral(gen *generators.Generator) *ast.CompositeLit {
+	var result *ast.CompositeLit
+	for _, file := range gen.Pkg.Syntax {
+		ast.Inspect(file, func(node ast.Node) bool {
+			if lit, ok := node.(*ast.CompositeLit); ok && lit.Pos() == gen.Pos {
+				result = lit
+			}
+			return result == nil
+		})
+	}
+	return result
+}
+
+// findFunc finds the package-level function declaration by name
+func findFunc(pkg *packages.Package, name string) *ast.FuncDecl {
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == name {
+				return fn
+			}
+		}
+	}
+	return nil
+}
+
+func funcKey(pkg *packages.Package, name string) string {
+	return core.MethodName(pkg.ID, name)
+}
+
+// legacyResource collects CRUD functions without context, nil is returned if there are none
+func (p *planner) legacyResource(gen *generators.Generator) *resource {
+	lit := findLiteral(gen)
+	if lit == nil {
+		return nil
+	}
+	res := &resource{Gen: gen, Lit: lit}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		if _, ok := legacyOperations[key.Name]; !ok {
+			continue
+		}
+		ident, ok := kv.Value.(*ast.Ident)
+		if !ok {
+			res.Refused = fmt.Sprintf("`%s` is not a function of the package", key.Name)
+			continue
+		}
+		decl := findFunc(gen.Pkg, ident.Name)
+		if decl == nil || decl.Body == nil {
+			res.Refused = fmt.Sprintf("`%s` function `%s` is not found", key.Name, ident.Name)
+			continue
+		}
+		if reason := unsafeSignature(decl); reason != "" {
+			res.Refused = reason
+		}
+		res.Operations = append(res.Operations, operation{Field: kv, Decl: decl})
+	}
+	if len(res.Operations) == 0 {
+		return nil
+	}
+	return res
+}
+
+// unsafeSignature checks that the function is `func(d *schema.ResourceData, meta interface{}) error`
+// and doesn't use `ctx` name
+func unsafeSignature(decl *ast.FuncDecl) string {
+	params, results := decl.Type.Params, decl.Type.Results
+	if params.NumFields() != 2 || results.NumFields() != 1 || len(results.List[0].Names) != 0 {
+		return fmt.Sprintf("`%s` signature is not `(d, meta) error`", decl.Name.Name)
+	}
+	if ident, ok := results.List[0].Type.(*ast.Ident); !ok || ident.Name != "error" {
+		return fmt.Sprintf("`%s` doesn't return `error`", decl.Name.Name)
+	}
+	reason := ""
+	ast.Inspect(decl, func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Ident); ok && ident.Name == "ctx" {
+			reason = fmt.Sprintf("`%s` already uses `ctx` name", decl.Name.Name)
+		}
+		return reason == ""
+	})
+	return reason
+}
+
+// returnedCall returns the name of the package function called in the `return` statement, e.g. `return read(d, meta)`
+func returnedCall(ret *ast.ReturnStmt) (*ast.CallExpr, string) {
+	if len(ret.Results) != 1 {
+		return nil, ""
+	}
+	call, ok := ret.Results[0].(*ast.CallExpr)
+	if !ok || len(call.Args) != 2 {
+		return nil, ""
+	}
+	ident, ok := call.Fun.(*ast.Ident)
+	if !ok {
+		return nil, ""
+	}
+	return call, ident.Name
+}
+
+// returns inspects `return` statements of the function, statements of function literals are skipped
+func returns(decl *ast.FuncDecl, fn func(ret *ast.ReturnStmt)) {
+	ast.Inspect(decl.Body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			fn(n)
+		}
+		return true
+	})
+}
+
+// refuseUnsafe refuses resources with functions used in other places than resource fields and
+// `return` statements of other migrated functions, until all remaining functions are safe
+func (p *planner) refuseUnsafe() {
+	for {
+		p.funcs = map[string]*ast.FuncDecl{}
+		allowed := map[token.Pos]bool{}
+		for _, res := range p.resources {
+			if res.Refused != "" {
+				continue
+			}
+			for _, op := range res.Operations {
+				p.funcs[funcKey(res.Gen.Pkg, op.Decl.Name.Name)] = op.Decl
+				allowed[op.Decl.Name.Pos()] = true
+				allowed[op.Field.Value.Pos()] = true
+			}
+		}
+		for _, res := range p.resources {
+			if res.Refused != "" {
+				continue
+			}
+			for _, op := range res.Operations {
+				returns(op.Decl, func(ret *ast.ReturnStmt) {
+					if call, name := returnedCall(ret); call != nil && p.funcs[funcKey(res.Gen.Pkg, name)] != nil {
+						allowed[call.Fun.Pos()] = true
+					}
+				})
+			}
+		}
+		changed := false
+		for _, res := range p.resources {
+			if res.Refused != "" {
+				continue
+			}
+			for _, op := range res.Operations {
+				if pos := p.otherUsage(res.Gen.Pkg, op.Decl.Name.Name, allowed); pos.IsValid() {
+					res.Refused = fmt.Sprintf("`%s` is also used at %s", op.Decl.Name.Name, p.position(pos))
+					changed = true
+					break
+				}
+			}
+		}
+		if !changed {
+			return
+		}
+	}
+}
+
+// otherUsage returns position of the identifier with the name which is not allowed, test files included
+func (p *planner) otherUsage(pkg *packages.Package, name string, allowed map[token.Pos]bool) token.Pos {
+	var found token.Pos
+	files := append(append([]*ast.File{}, pkg.Syntax...), p.tests[pkg.PkgPath]...)
+	for _, file := range files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch n := node.(type) {
+			case *ast.SelectorExpr:
+				ast.Inspect(n.X, func(inner ast.Node) bool {
+					if ident, ok := inner.(*ast.Ident); ok && ident.Name == name && !allowed[ident.Pos()] {
+						found = ident.Pos()
+					}
+					return !found.IsValid()
+				})
+				return false // selected names are fields and methods
+			case *ast.Ident:
+				if n.Name == name && !allowed[n.Pos()] {
+					found = n.Pos()
+				}
+			}
+			return !found.IsValid()
+		})
+		if found.IsValid() {
+			return found
+		}
+	}
+	return found
+}
+
+// migrateResource renames CRUD fields and migrates their functions
+func (p *planner) migrateResource(res *resource) error {
+	for _, op := range res.Operations {
+		key := op.Field.Key.(*ast.Ident)
+		p.replace(key.Pos(), key.End(), legacyOperations[key.Name])
+		if p.migrated[op.Decl] {
+			continue // the same function is used by several fields or resources
+		}
+		p.migrated[op.Decl] = true
+		if err := p.migrateFunc(res.Gen, op.Decl); err != nil {
+			return err
+		}
+	}
+	p.migrateImporter(res)
+	return nil
+}
+
+// migrateFunc changes the signature to `(ctx, d, meta) diag.Diagnostics` and rewrites returned values
+func (p *planner) migrateFunc(gen *generators.Generator, decl *ast.FuncDecl) error {
+	file := fileOf(gen.Pkg, decl.Pos())
+	contextName, err := p.importName(file, "context")
+	if err != nil {
+		return p.fileError(decl, err)
+	}
+	diagName, err := p.importName(file, sdkPath(gen, "diag"))
+	if err != nil {
+		return p.fileError(decl, err)
+	}
+	errorfName := diagName + ".Errorf"
+	if p.opts.Errorf != "" {
+		pkgPath, fn := splitFullName(p.opts.Errorf)
+		name, err := p.importName(file, pkgPath)
+		if err != nil {
+			return p.fileError(decl, err)
+		}
+		errorfName = name + "." + fn
+	}
+
+	params := decl.Type.Params
+	p.insert(params.Opening+1, fmt.Sprintf("ctx %s.Context, ", contextName))
+	result := decl.Type.Results.List[0].Type
+	p.replace(result.Pos(), result.End(), diagName+".Diagnostics")
+
+	returns(decl, func(ret *ast.ReturnStmt) {
+		if len(ret.Results) != 1 {
+			return
+		}
+		value := ret.Results[0]
+		if ident, ok := value.(*ast.Ident); ok && ident.Name == "nil" {
+			return
+		}
+		if call, name := returnedCall(ret); call != nil && p.funcs[funcKey(gen.Pkg, name)] != nil {
+			p.insert(call.Args[0].Pos(), "ctx, ")
+			return
+		}
+		if call, ok := value.(*ast.CallExpr); ok && isPackageCall(file, call, "fmt", "Errorf") {
+			if p.opts.Errorf != "" {
+				p.replace(call.Fun.Pos(), call.Fun.End(), errorfName)
+				return
+			}
+			// `diag.Errorf` formats with `fmt.Sprintf`, so `%w` verbs are replaced
+			if format, ok := call.Args[0].(*ast.BasicLit); ok && format.Kind == token.STRING {
+				p.replace(call.Fun.Pos(), call.Fun.End(), errorfName)
+				if strings.Contains(format.Value, "%w") {
+					p.replace(format.Pos(), format.End(), strings.ReplaceAll(format.Value, "%w", "%s"))
+				}
+				return
+			}
+		}
+		p.insert(value.Pos(), diagName+".FromErr(")
+		p.insert(value.End(), ")")
+	})
+	return p.migrateRetries(gen, file, decl)
+}
+
+func (p *planner) fileError(node ast.Node, err error) error {
+	return fmt.Errorf("%s - can't add import: %w", p.position(node.Pos()), err)
+}
+
+// splitFullName splits `<import path>.<name>` into the path and the name
+func splitFullName(name string) (string, string) {
+	dot := strings.LastIndex(name, ".")
+	if dot < 0 {
+		return "", name
+	}
+	return name[:dot], name[dot+1:]
+}
+
+// isPackageCall checks if the call is `<pkg>.<name>(...)` of the package with the import path
+func isPackageCall(file *ast.File, call *ast.CallExpr, importPath, name string) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	if !ok || x.Obj != nil {
+		return false
+	}
+	for _, imp := range file.Imports {
+		val, _ := core.UnwrapString(imp.Path)
+		if val != importPath {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name == x.Name
+		}
+		return path.Base(val) == x.Name
+	}
+	return false
+}
+
+// migrateRetries rewrites `resource.Retry(timeout, f)` to `resource.RetryContext(ctx, timeout, f)`
+func (p *planner) migrateRetries(gen *generators.Generator, file *ast.File, decl *ast.FuncDecl) error {
+	resourcePath := sdkPath(gen, "helper/resource")
+	ast.Inspect(decl.Body, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || len(call.Args) != 2 || !isPackageCall(file, call, resourcePath, "Retry") {
+			return true
+		}
+		sel := call.Fun.(*ast.SelectorExpr)
+		p.replace(sel.Sel.Pos(), sel.Sel.End(), "RetryContext")
+		p.insert(call.Args[0].Pos(), "ctx, ")
+		p.noted[call.Pos()] = true // migrated, no need for a note
+		return true
+	})
+	return nil
+}
+
+// migrateImporter replaces `State: schema.ImportStatePassthrough` importer, custom importers are reported
+func (p *planner) migrateImporter(res *resource) {
+	for _, elt := range res.Lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if key, ok := kv.Key.(*ast.Ident); !ok || key.Name != "Importer" {
+			continue
+		}
+		value := kv.Value
+		if unary, ok := value.(*ast.UnaryExpr); ok {
+			value = unary.X
+		}
+		lit, ok := value.(*ast.CompositeLit)
+		if !ok {
+			return
+		}
+		for _, importerElt := range lit.Elts {
+			field, ok := importerElt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			if key, ok := field.Key.(*ast.Ident); !ok || key.Name != "State" {
+				continue
+			}
+			if sel, ok := field.Value.(*ast.SelectorExpr); ok && sel.Sel.Name == "ImportStatePassthrough" {
+				p.replace(field.Key.Pos(), field.Key.End(), "StateContext")
+				p.replace(sel.Sel.Pos(), sel.Sel.End(), "ImportStatePassthroughContext")
+				continue
+			}
+			p.note(field.Pos(), "custom importer `State` function is not migrated, use `StateContext`")
+		}
+	}
+}
+
+// migrateValidators replaces `ValidateFunc: f` with `ValidateDiagFunc: validation.ToDiagFunc(f)`
+func (p *planner) migrateValidators(gen *generators.Generator, schema map[string]*generators.Field) error {
+	keys := make([]string, 0, len(schema))
+	for key := range schema {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fld := schema[key]
+		if fld == nil {
+			continue
+		}
+		if err := p.migrateValidators(gen, fld.Schema); err != nil {
+			return err
+		}
+		pos, ok := fld.Attrs["ValidateFunc"]
+		if !ok || p.noted[pos] {
+			continue
+		}
+		p.noted[pos] = true // fields declared by helpers are shared by resources
+		file := fileOf(gen.Pkg, pos)
+		if file == nil {
+			p.migration.Notes = append(p.migration.Notes, Note{
+				Pos: p.position(pos), Message: "`ValidateFunc` of the schema declared in other package is not migrated",
+			})
+			continue
+		}
+		if _, ok := fld.Attrs["ValidateDiagFunc"]; ok {
+			p.migration.Notes = append(p.migration.Notes, Note{
+				Pos: p.position(pos), Message: "field has both `ValidateFunc` and `ValidateDiagFunc`",
+			})
+			continue
+		}
+		kv := findKeyValue(file, pos)
+		if kv == nil {
+			continue
+		}
+		name, err := p.importName(file, sdkPath(gen, "helper/validation"))
+		if err != nil {
+			return p.fileError(kv, err)
+		}
+		p.replace(kv.Key.Pos(), kv.Key.End(), "ValidateDiagFunc")
+		p.insert(kv.Value.Pos(), name+".ToDiagFunc(")
+		p.insert(kv.Value.End(), ")")
+	}
+	return nil
+}
+
+// findKeyValue finds the key-value expression at the position
+func findKeyValue(file *ast.File, pos token.Pos) *ast.KeyValueExpr {
+	var result *ast.KeyValueExpr
+	ast.Inspect(file, func(node ast.Node) bool {
+		if kv, ok := node.(*ast.KeyValueExpr); ok && kv.Pos() == pos {
+			result = kv
+		}
+		return result == nil
+	})
+	return result
+}
+
+// noteLegacyCalls reports `d.Partial`, `d.SetPartial` and `resource.Retry` calls which are not rewritten
+func (p *planner) noteLegacyCalls(gen *generators.Generator) {
+	if gen.SDK.Generation != core.SDKv2 {
+		return
+	}
+	resourcePath := sdkPath(gen, "helper/resource")
+	for _, file := range gen.Pkg.Syntax {
+		ast.Inspect(file, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			if isPackageCall(file, call, resourcePath, "Retry") {
+				p.note(call.Pos(), "`resource.Retry` is used without context, use `resource.RetryContext`")
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "Partial" || len(call.Args) != 1 {
+				return true
+			}
+			if arg, ok := call.Args[0].(*ast.Ident); ok && (arg.Name == "true" || arg.Name == "false") {
+				p.note(call.Pos(), "`d.Partial` is deprecated, remove it unless the resource relies on partial state")
+			}
+			return true
+		})
+	}
+}
@@ -17,23 +17,28 @@ import (
 type PackageParser struct {
 	fSet       *token.FileSet
 	pkg        *packages.Package
+	config     *core.Config
+	project    *core.Project
 	scopeCache map[string]*core.Scope
 }
 
-func NewParser(pkg *packages.Package, set *token.FileSet, scopeCache map[string]*core.Scope) *PackageParser {
+func NewParser(pkg *packages.Package, set *token.FileSet, config *core.Config, project *core.Project, scopeCache map[string]*core.Scope) *PackageParser {
 	p := &PackageParser{
 		pkg:        pkg,
 		fSet:       set,
+		config:     config,
+		project:    project,
 		scopeCache: scopeCache,
 	}
 	return p
 }
 
-func (p PackageParser) ParseGenerator(lit *ast.CompositeLit, genName string) (*generators.Generator, error) {
-	gen, err := generators.NewGenerator(genName, p.fSet, p.pkg, p.scopeCache)
+func (p PackageParser) ParseGenerator(lit *ast.CompositeLit, genName string, sdk core.SchemaImport) (*generators.Generator, error) {
+	gen, err := generators.NewGenerator(genName, p.fSet, p.pkg, p.config, p.project, p.scopeCache)
 	if err != nil {
 		return nil, fmt.Errorf("error creating generator: %w", err)
 	}
+	gen.SDK = sdk
 	err = gen.LoadSchema(lit)
 	if err != nil {
 		return nil, fmt.Errorf("error loading generator schema: %w", err)
@@ -41,33 +46,52 @@ func (p PackageParser) ParseGenerator(lit *ast.CompositeLit, genName string) (*g
 	return gen, nil
 }
 
-func getSchemaImportName(file *ast.File) string {
+// getSchemaImport returns the name of the schema package import and the package used in the file,
+// files importing unknown schema packages are reported
+func (p PackageParser) getSchemaImport(file *ast.File) (string, core.SchemaImport) {
 	for _, imp := range file.Imports {
 		val, _ := core.UnwrapString(imp.Path)
-		if val != core.SchemaImportPath {
+		generation, ok := p.config.SchemaGeneration(val)
+		if !ok {
+			if core.IsSchemaPackage(val) {
+				log.Printf("%s imports unknown schema package %s, add it to `schema_imports` to validate its resources",
+					simplePath(p.fSet.Position(file.Package).Filename), val)
+			}
 			continue
 		}
+		sdk := core.SchemaImport{Path: val, Generation: generation}
 		if imp.Name != nil {
-			return imp.Name.Name
+			return imp.Name.Name, sdk
 		}
-		return filepath.Base(val) // set alias to module name
+		return filepath.Base(val), sdk // set alias to module name
 	}
-	return ""
+	return "", core.SchemaImport{}
+}
+
+// simplePath returns the file name with the parent directory, e.g. `vpc/resource_vpc.go`
+func simplePath(path string) string {
+	return filepath.Join(filepath.Base(filepath.Dir(path)), filepath.Base(path))
+}
+
+// generatorFn is a function returning `*schema.Resource` of the SDK package
+type generatorFn struct {
+	Obj *ast.Object
+	SDK core.SchemaImport
 }
 
-func (p PackageParser) GeneratorFns() map[string]*ast.Object {
-	gens := map[string]*ast.Object{}
+func (p PackageParser) GeneratorFns() map[string]generatorFn {
+	gens := map[string]generatorFn{}
 	files := p.pkg.Syntax
 	for _, f := range files {
+		schemaImportName, sdk := p.getSchemaImport(f)
+		if schemaImportName == "" {
+			continue // no `schema` import found, skip the file
+		}
 		for name, obj := range f.Scope.Objects {
-			schemaImportName := getSchemaImportName(f)
-			if schemaImportName == "" {
-				continue // no `schema` import found, skip the file
-			}
 			if !isGeneratorFn(obj, schemaImportName) {
 				continue
 			}
-			gens[name] = obj
+			gens[name] = generatorFn{Obj: obj, SDK: sdk}
 		}
 	}
 	return gens
@@ -93,26 +117,42 @@ func isGeneratorFn(obj *ast.Object, schemaImportName string) bool {
 	return sel.X.(*ast.Ident).Name == schemaImportName && sel.Sel.Name == "Resource"
 }
 
-func (p PackageParser) Validate() error {
+// Generators parses all generators of the package, generators which can't be parsed are skipped
+func (p PackageParser) Generators() []*generators.Generator {
 	generatorFns := p.GeneratorFns()
-	if l := len(generatorFns); l != 0 {
-		log.Printf("found %d generator(s) in package %s", l, p.pkg.ID)
+	generations := map[string]int{}
+	for _, fn := range generatorFns {
+		generations[fn.SDK.Generation]++
 	}
-	mErr := &multierror.Error{}
-	for name, fnObj := range generatorFns {
-		ast.Inspect(fnObj.Decl.(*ast.FuncDecl), func(node ast.Node) bool {
+	for _, generation := range []string{core.SDKv1, core.SDKv2} {
+		if l := generations[generation]; l != 0 {
+			log.Printf("found %d generator(s) using SDK %s in package %s", l, generation, p.pkg.ID)
+		}
+	}
+	var gens []*generators.Generator
+	for name, fn := range generatorFns {
+		sdk := fn.SDK
+		ast.Inspect(fn.Obj.Decl.(*ast.FuncDecl), func(node ast.Node) bool {
 			lit, ok := node.(*ast.CompositeLit)
 			if !ok {
 				return true
 			}
-			gen, err := p.ParseGenerator(lit, name)
+			gen, err := p.ParseGenerator(lit, name, sdk)
 			if err != nil {
 				log.Println(err)
 				return false
 			}
-			mErr = multierror.Append(mErr, gen.ValidateSetters())
+			gens = append(gens, gen)
 			return false
 		})
 	}
+	return gens
+}
+
+func (p PackageParser) Validate() error {
+	mErr := &multierror.Error{}
+	for _, gen := range p.Generators() {
+		mErr = multierror.Append(mErr, gen.Validate())
+	}
 	return mErr
 }
@@ -0,0 +1,91 @@
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+	"golang.org/x/tools/go/packages"
+)
+
+// providerMaps are provider fields registering resources, with data source flag
+var providerMaps = map[string]bool{
+	"ResourcesMap":   false,
+	"DataSourcesMap": true,
+}
+
+// IsTestVariant checks if the package is a test variant loaded in addition to the original package
+func IsTestVariant(pkg *packages.Package) bool {
+	return strings.Contains(pkg.ID, " [") || strings.HasSuffix(pkg.ID, ".test")
+}
+
+// FindResources searches for `ResourcesMap` and `DataSourcesMap` of the provider
+// and returns resource registrations keyed by generator function (`<package>.<function>`)
+func FindResources(pkgs []*packages.Package, fSet *token.FileSet) map[string]core.ResourceInfo {
+	result := map[string]core.ResourceInfo{}
+	for _, pkg := range pkgs {
+		if IsTestVariant(pkg) {
+			continue
+		}
+		for _, file := range pkg.Syntax {
+			ast.Inspect(file, func(node ast.Node) bool {
+				kv, ok := node.(*ast.KeyValueExpr)
+				if !ok {
+					return true
+				}
+				key, ok := kv.Key.(*ast.Ident)
+				if !ok {
+					return true
+				}
+				dataSource, ok := providerMaps[key.Name]
+				if !ok {
+					return true
+				}
+				lit, ok := kv.Value.(*ast.CompositeLit)
+				if !ok {
+					return true
+				}
+				for _, elt := range lit.Elts {
+					addResource(result, elt, dataSource, pkg, fSet)
+				}
+				return false
+			})
+		}
+	}
+	return result
+}
+
+func addResource(result map[string]core.ResourceInfo, elt ast.Expr, dataSource bool, pkg *packages.Package, fSet *token.FileSet) {
+	kv, ok := elt.(*ast.KeyValueExpr)
+	if !ok {
+		return
+	}
+	name, ok := kv.Key.(*ast.BasicLit)
+	if !ok {
+		return
+	}
+	typeName, _ := core.UnwrapString(name)
+	call, ok := kv.Value.(*ast.CallExpr)
+	if !ok {
+		return
+	}
+	var fnName string
+	switch fn := call.Fun.(type) {
+	case *ast.Ident:
+		fnName = core.MethodName(pkg.ID, fn.Name)
+	case *ast.SelectorExpr:
+		x, ok := fn.X.(*ast.Ident)
+		if !ok {
+			return
+		}
+		path := core.AbsoluteImport(fSet, x.Name, fn, pkg)
+		if path == "" {
+			return
+		}
+		fnName = core.MethodName(path, fn.Sel.Name)
+	default:
+		return
+	}
+	result[fnName] = core.ResourceInfo{TypeName: typeName, DataSource: dataSource}
+}
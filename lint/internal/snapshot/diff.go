@@ -0,0 +1,257 @@
+package snapshot
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// ChangeKind classifies schema change by its impact on users
+type ChangeKind string
+
+const (
+	// Breaking changes require users to change configuration
+	Breaking ChangeKind = "breaking"
+	// StateMigrating changes keep configuration valid, but change the state representation
+	StateMigrating ChangeKind = "state-migrating"
+	// Additive changes are backward compatible
+	Additive ChangeKind = "additive"
+)
+
+// ChangeKinds are all change kinds in the order of severity
+var ChangeKinds = []ChangeKind{Breaking, StateMigrating, Additive}
+
+// Title returns human-readable name of the change kind
+func (k ChangeKind) Title() string {
+	switch k {
+	case Breaking:
+		return "Breaking"
+	case StateMigrating:
+		return "State-migrating"
+	case Additive:
+		return "Additive"
+	}
+	return string(k)
+}
+
+// Action is what happened to the resource or the attribute
+type Action string
+
+// Actions of the change, attribute type or flag changes are `Modified`
+const (
+	Added      Action = "added"
+	Removed    Action = "removed"
+	Deprecated Action = "deprecated"
+	Modified   Action = "modified"
+)
+
+// Change is a single schema change
+type Change struct {
+	Kind   ChangeKind
+	Action Action
+	// Resource is a type name, data sources have `data.` prefix
+	Resource string
+	// Path is the attribute path, empty for changes of the whole resource
+	Path    string
+	Message string
+}
+
+func (c Change) String() string {
+	if c.Path == "" {
+		return fmt.Sprintf("%s: %s", c.Resource, c.Message)
+	}
+	return fmt.Sprintf("%s: attribute `%s` %s", c.Resource, c.Path, c.Message)
+}
+
+// primitiveTypes are types which can be converted one to another with state migration
+var primitiveTypes = map[string]bool{
+	"TypeBool":   true,
+	"TypeInt":    true,
+	"TypeFloat":  true,
+	"TypeString": true,
+}
+
+type differ struct {
+	resource string
+	changes  []Change
+}
+
+func (d *differ) add(kind ChangeKind, path, format string, args ...interface{}) {
+	d.addAction(kind, Modified, path, format, args...)
+}
+
+func (d *differ) addAction(kind ChangeKind, action Action, path, format string, args ...interface{}) {
+	d.changes = append(d.changes, Change{
+		Kind:     kind,
+		Action:   action,
+		Resource: d.resource,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Diff returns all changes between the schemas, resources are matched by type names
+func Diff(prev, next *Provider) []Change {
+	var changes []Change
+	changes = append(changes, diffResources(prev.Resources, next.Resources, "")...)
+	changes = append(changes, diffResources(prev.DataSources, next.DataSources, "data.")...)
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Resource != changes[j].Resource {
+			return changes[i].Resource < changes[j].Resource
+		}
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+func diffResources(prev, next map[string]*Resource, prefix string) []Change {
+	var changes []Change
+	for name, prevRes := range prev {
+		nextRes, ok := next[name]
+		if !ok {
+			changes = append(changes, Change{Kind: Breaking, Action: Removed, Resource: prefix + name, Message: "removed"})
+			continue
+		}
+		changes = append(changes, DiffResource(prefix+name, prevRes, nextRes)...)
+	}
+	for name := range next {
+		if _, ok := prev[name]; !ok {
+			changes = append(changes, Change{Kind: Additive, Action: Added, Resource: prefix + name, Message: "added"})
+		}
+	}
+	return changes
+}
+
+// DiffResource returns changes between two versions of the resource schema
+func DiffResource(name string, prev, next *Resource) []Change {
+	d := &differ{resource: name}
+	d.diffSchema(prev.Schema, next.Schema, "")
+	return d.changes
+}
+
+func (d *differ) diffSchema(prev, next map[string]*Attribute, prefix string) {
+	for key, prevAttr := range prev {
+		path := prefix + key
+		nextAttr, ok := next[key]
+		if !ok {
+			d.addAction(Breaking, Removed, path, "removed")
+			continue
+		}
+		d.diffAttribute(prevAttr, nextAttr, path)
+	}
+	for key, nextAttr := range next {
+		if _, ok := prev[key]; ok {
+			continue
+		}
+		if nextAttr.Required {
+			d.addAction(Breaking, Added, prefix+key, "added as required")
+		} else {
+			d.addAction(Additive, Added, prefix+key, "added")
+		}
+	}
+}
+
+// typeChangeKind returns kind of the type change, the configuration is valid for both list and set,
+// primitive values are converted by Terraform
+func typeChangeKind(prev, next string) ChangeKind {
+	if primitiveTypes[prev] && primitiveTypes[next] {
+		return StateMigrating
+	}
+	if (prev == "TypeList" || prev == "TypeSet") && (next == "TypeList" || next == "TypeSet") {
+		return StateMigrating
+	}
+	return Breaking
+}
+
+func (d *differ) diffAttribute(prev, next *Attribute, path string) {
+	if prev.Type != next.Type && prev.Type != "" && next.Type != "" {
+		d.add(typeChangeKind(prev.Type, next.Type), path, "type changed from `%s` to `%s`", prev.Type, next.Type)
+	}
+	d.diffFlag(path, "required", prev.Required, next.Required, Breaking, Additive)
+	d.diffFlag(path, "optional", prev.Optional && !next.Required, next.Optional, Additive, Breaking)
+	d.diffFlag(path, "computed", prev.Computed, next.Computed, Additive, Breaking)
+	d.diffFlag(path, "`ForceNew`", prev.ForceNew, next.ForceNew, Breaking, Additive)
+	d.diffMaxItems(prev.MaxItems, next.MaxItems, path)
+	if prev.Deprecated == "" && next.Deprecated != "" {
+		d.addAction(Additive, Deprecated, path, "deprecated: %s", next.Deprecated)
+	}
+
+	switch {
+	case prev.Schema != nil && next.Schema != nil:
+		d.diffSchema(prev.Schema, next.Schema, path+".0.")
+	case prev.Elem != nil && next.Elem != nil:
+		if prev.Elem.Type != next.Elem.Type && prev.Elem.Type != "" && next.Elem.Type != "" {
+			d.add(typeChangeKind(prev.Elem.Type, next.Elem.Type), path,
+				"element type changed from `%s` to `%s`", prev.Elem.Type, next.Elem.Type)
+		}
+	case prev.Schema != nil && next.Elem != nil:
+		d.add(Breaking, path, "elements changed from blocks to primitives")
+	case prev.Elem != nil && next.Schema != nil:
+		d.add(Breaking, path, "elements changed from primitives to blocks")
+	}
+}
+
+// diffFlag reports flag changes, `added` and `removed` are kinds of the flag being set and unset
+func (d *differ) diffFlag(path, name string, prev, next bool, added, removed ChangeKind) {
+	switch {
+	case !prev && next:
+		d.add(added, path, "became %s", name)
+	case prev && !next:
+		d.add(removed, path, "is no longer %s", name)
+	}
+}
+
+func (d *differ) diffMaxItems(prev, next int, path string) {
+	switch {
+	case prev == next:
+	case next != 0 && (prev == 0 || next < prev):
+		d.add(Breaking, path, "has `MaxItems` lowered from %s to %d", maxItemsString(prev), next)
+	default:
+		d.add(Additive, path, "has `MaxItems` raised from %d to %s", prev, maxItemsString(next))
+	}
+}
+
+func maxItemsString(v int) string {
+	if v == 0 {
+		return "unlimited"
+	}
+	return fmt.Sprint(v)
+}
+
+// HasBreaking checks if there are breaking changes
+func HasBreaking(changes []Change) bool {
+	for _, c := range changes {
+		if c.Kind == Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteReport writes changes grouped by kind
+func WriteReport(w io.Writer, changes []Change) error {
+	if len(changes) == 0 {
+		_, err := fmt.Fprintln(w, "No schema changes found")
+		return err
+	}
+	for _, kind := range ChangeKinds {
+		var group []Change
+		for _, c := range changes {
+			if c.Kind == kind {
+				group = append(group, c)
+			}
+		}
+		if len(group) == 0 {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "%s changes (%d):\n", kind.Title(), len(group)); err != nil {
+			return err
+		}
+		for _, c := range group {
+			if _, err := fmt.Fprintf(w, "  - %s\n", c); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+// Package snapshot contains provider schema representation independent of the source code,
+// which can be stored as JSON and compared between versions
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/generators"
+)
+
+// Attribute is a single schema field, `Default` is the value expression, e.g. `"tcp"` or `true`
+type Attribute struct {
+	Type        string                `json:"type"`
+	Required    bool                  `json:"required,omitempty"`
+	Optional    bool                  `json:"optional,omitempty"`
+	Computed    bool                  `json:"computed,omitempty"`
+	ForceNew    bool                  `json:"force_new,omitempty"`
+	Sensitive   bool                  `json:"sensitive,omitempty"`
+	MaxItems    int                   `json:"max_items,omitempty"`
+	Deprecated  string                `json:"deprecated,omitempty"`
+	Default     string                `json:"default,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Elem        *Attribute            `json:"elem,omitempty"`
+	Schema      map[string]*Attribute `json:"schema,omitempty"`
+}
+
+// Resource is a resource or a data source schema
+type Resource struct {
+	SchemaVersion int                   `json:"schema_version,omitempty"`
+	Schema        map[string]*Attribute `json:"schema"`
+}
+
+// Provider contains schemas of all resources and data sources by type names
+type Provider struct {
+	Resources   map[string]*Resource `json:"resources"`
+	DataSources map[string]*Resource `json:"data_sources"`
+}
+
+func newAttribute(fld *generators.Field) *Attribute {
+	attr := &Attribute{
+		Type:        fld.Type,
+		Required:    fld.Required,
+		Optional:    fld.Optional,
+		Computed:    fld.Computed,
+		ForceNew:    fld.ForceNew,
+		Sensitive:   fld.Sensitive,
+		MaxItems:    fld.MaxItems,
+		Deprecated:  fld.Deprecated,
+		Description: fld.Description,
+	}
+	if fld.Default != nil {
+		attr.Default = types.ExprString(fld.Default)
+	}
+	if fld.Elem != nil {
+		attr.Elem = newAttribute(fld.Elem)
+	}
+	if fld.Schema != nil {
+		attr.Schema = newSchema(fld.Schema)
+	}
+	return attr
+}
+
+func newSchema(schema map[string]*generators.Field) map[string]*Attribute {
+	result := make(map[string]*Attribute, len(schema))
+	for key, fld := range schema {
+		if fld != nil {
+			result[key] = newAttribute(fld)
+		}
+	}
+	return result
+}
+
+// FromGenerator creates snapshot of the single generator schema
+func FromGenerator(gen *generators.Generator) *Resource {
+	res := &Resource{Schema: newSchema(gen.Schema)}
+	if gen.SchemaVersion > 0 {
+		res.SchemaVersion = gen.SchemaVersion
+	}
+	return res
+}
+
+// hasProviderMaps checks if resources of the generators are registered in provider maps
+func hasProviderMaps(gens []*generators.Generator) bool {
+	for _, gen := range gens {
+		if gen.Project != nil && len(gen.Project.Resources) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// FromGenerators creates snapshot of the generator schemas. Generators not registered in the provider,
+// e.g. nested `Elem` helpers, are skipped, they are stored as resources by the function name
+// only if there are no provider maps
+func FromGenerators(gens []*generators.Generator) *Provider {
+	p := &Provider{
+		Resources:   map[string]*Resource{},
+		DataSources: map[string]*Resource{},
+	}
+	registeredOnly := hasProviderMaps(gens)
+	for _, gen := range gens {
+		if registeredOnly && gen.Resource.TypeName == "" {
+			continue
+		}
+		res := FromGenerator(gen)
+		switch {
+		case gen.Resource.TypeName == "":
+			p.Resources[gen.Name] = res
+		case gen.Resource.DataSource:
+			p.DataSources[gen.Resource.TypeName] = res
+		default:
+			p.Resources[gen.Resource.TypeName] = res
+		}
+	}
+	return p
+}
+
+// ReadFile loads snapshot from the JSON file
+func ReadFile(path string) (*Provider, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("error reading schema file: %w", err)
+	}
+	p := &Provider{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("error parsing schema file %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Write writes snapshot as JSON
+func (p *Provider) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(p); err != nil {
+		return fmt.Errorf("error serializing schema: %w", err)
+	}
+	return nil
+}
+
+// WriteFile stores snapshot as JSON file
+func (p *Provider) WriteFile(path string) error {
+	f, err := os.Create(filepath.Clean(path))
+	if err != nil {
+		return fmt.Errorf("error creating schema file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	return p.Write(f)
+}
@@ -0,0 +1,207 @@
+// Package upgrade validates state upgrades of resources against the baseline schema snapshot
+package upgrade
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/generators"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/snapshot"
+)
+
+// Validate checks that resources with state-incompatible schema changes since the baseline
+// bump `SchemaVersion` and have an upgrader for the baseline version, intentional changes of the config are skipped
+func Validate(gens []*generators.Generator, baseline *snapshot.Provider, config *core.Config) error {
+	mErr := &multierror.Error{}
+	for _, gen := range gens {
+		if gen.Resource.TypeName == "" || gen.Resource.DataSource {
+			continue
+		}
+		prev, ok := baseline.Resources[gen.Resource.TypeName]
+		if !ok {
+			continue // new resources have no state to upgrade
+		}
+		mErr = multierror.Append(mErr, validateResource(gen, prev, config))
+	}
+	return mErr.ErrorOrNil()
+}
+
+func validateResource(gen *generators.Generator, prev *snapshot.Resource, config *core.Config) error {
+	if gen.SchemaVersion < 0 {
+		return nil
+	}
+	next := snapshot.FromGenerator(gen)
+	var upgrader *generators.StateUpgrader
+	for i, u := range gen.StateUpgraders {
+		if u.Version == prev.SchemaVersion {
+			upgrader = &gen.StateUpgraders[i]
+		}
+	}
+
+	mErr := &multierror.Error{}
+	if changes := incompatibleChanges(gen.Resource.TypeName, prev, next, config); len(changes) != 0 {
+		summary := strings.Join(changes, "; ")
+		if gen.SchemaVersion <= prev.SchemaVersion {
+			mErr = multierror.Append(mErr, gen.RuleError(core.RuleStateUpgrade, gen.Pos,
+				"state-incompatible changes require `SchemaVersion` bump from %d: %s", prev.SchemaVersion, summary))
+		}
+		if upgrader == nil {
+			mErr = multierror.Append(mErr, gen.RuleError(core.RuleStateUpgrade, gen.Pos,
+				"state-incompatible changes require `StateUpgraders` entry for version %d: %s", prev.SchemaVersion, summary))
+		}
+	}
+	if upgrader != nil && upgrader.Upgrade != nil {
+		// the upgraded state matches the current schema only if there are no intermediate versions
+		var current map[string]*snapshot.Attribute
+		if gen.SchemaVersion == prev.SchemaVersion+1 {
+			current = next.Schema
+		}
+		mErr = multierror.Append(mErr, validateStateAccess(gen, upgrader, prev.Schema, current))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// incompatibleChanges describes changes of the state representation which can't be handled by Terraform
+func incompatibleChanges(name string, prev, next *snapshot.Resource, config *core.Config) []string {
+	var result []string
+	for _, c := range snapshot.DiffResource(name, prev, next) {
+		if c.Kind == snapshot.StateMigrating && !config.IsIntentionalChange(name, c.Path) {
+			result = append(result, fmt.Sprintf("`%s` %s", c.Path, c.Message))
+		}
+	}
+	intentional := func(path string) bool {
+		return config.IsIntentionalChange(name, path)
+	}
+	return append(result, renames(prev.Schema, next.Schema, "", intentional)...)
+}
+
+// renames finds removed attributes with added attributes of the same type on the same level,
+// the value of the renamed attribute is lost without the state upgrade. It's only a guess, so
+// attributes removed intentionally are skipped
+func renames(prev, next map[string]*snapshot.Attribute, prefix string, intentional func(path string) bool) []string {
+	var removed, added []string
+	for key := range prev {
+		if _, ok := next[key]; !ok && !intentional(prefix+key) {
+			removed = append(removed, key)
+		}
+	}
+	for key := range next {
+		if _, ok := prev[key]; !ok {
+			added = append(added, key)
+		}
+	}
+	sort.Strings(removed)
+	sort.Strings(added)
+
+	var result []string
+	for _, oldKey := range removed {
+		for i, newKey := range added {
+			if newKey == "" || !sameType(prev[oldKey], next[newKey]) {
+				continue
+			}
+			result = append(result, fmt.Sprintf("`%s%s` looks renamed to `%s%s`", prefix, oldKey, prefix, newKey))
+			added[i] = ""
+			break
+		}
+	}
+	for key, prevAttr := range prev {
+		if nextAttr, ok := next[key]; ok && prevAttr.Schema != nil && nextAttr.Schema != nil {
+			result = append(result, renames(prevAttr.Schema, nextAttr.Schema, prefix+key+".0.", intentional)...)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func sameType(a, b *snapshot.Attribute) bool {
+	if a.Type != b.Type || (a.Elem == nil) != (b.Elem == nil) || (a.Schema == nil) != (b.Schema == nil) {
+		return false
+	}
+	return a.Elem == nil || a.Elem.Type == b.Elem.Type
+}
+
+// stateParam returns name of the upgrade function parameter holding the raw state
+func stateParam(fn *ast.FuncDecl) string {
+	for _, field := range fn.Type.Params.List {
+		if _, ok := field.Type.(*ast.MapType); ok && len(field.Names) != 0 {
+			return field.Names[0].Name
+		}
+	}
+	return ""
+}
+
+// stateKey returns key of the `rawState["key"]` expression
+func stateKey(expr ast.Expr, param string) (string, bool) {
+	idx, ok := expr.(*ast.IndexExpr)
+	if !ok {
+		return "", false
+	}
+	if ident, ok := idx.X.(*ast.Ident); !ok || ident.Name != param {
+		return "", false
+	}
+	lit, ok := idx.Index.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	key, _ := core.UnwrapString(lit)
+	return key, true
+}
+
+// validateStateAccess checks that the upgrader reads keys of the old schema and writes keys of the new one,
+// writes are not checked if the new schema is nil
+func validateStateAccess(gen *generators.Generator, upgrader *generators.StateUpgrader, prev, next map[string]*snapshot.Attribute) error {
+	param := stateParam(upgrader.Upgrade)
+	if param == "" || upgrader.Upgrade.Body == nil {
+		return nil
+	}
+	mErr := &multierror.Error{}
+	read := func(key string, pos token.Pos) {
+		if _, ok := prev[key]; !ok && key != "id" {
+			mErr = multierror.Append(mErr, gen.RuleError(core.RuleStateUpgrade, pos,
+				"state upgrader reads `%s` missing in the schema version %d", key, upgrader.Version))
+		}
+	}
+	written := map[ast.Expr]bool{}
+	ast.Inspect(upgrader.Upgrade.Body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				key, ok := stateKey(lhs, param)
+				if !ok {
+					continue
+				}
+				written[lhs] = true
+				if _, ok := next[key]; !ok && next != nil && key != "id" {
+					mErr = multierror.Append(mErr, gen.RuleError(core.RuleStateUpgrade, lhs.Pos(),
+						"state upgrader writes `%s` missing in the current schema", key))
+				}
+			}
+		case *ast.CallExpr:
+			fn, ok := n.Fun.(*ast.Ident)
+			if !ok || fn.Name != "delete" || len(n.Args) != 2 {
+				return true
+			}
+			if ident, ok := n.Args[0].(*ast.Ident); !ok || ident.Name != param {
+				return true
+			}
+			if lit, ok := n.Args[1].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				key, _ := core.UnwrapString(lit)
+				read(key, lit.Pos())
+			}
+		case *ast.IndexExpr:
+			if written[n] {
+				return true
+			}
+			if key, ok := stateKey(n, param); ok {
+				read(key, n.Pos())
+			}
+		}
+		return true
+	})
+	return mErr.ErrorOrNil()
+}
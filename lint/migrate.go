@@ -0,0 +1,93 @@
+package lint
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/migrate"
+)
+
+// MigrationOptions configures rewrites of legacy SDK v2 APIs
+type MigrationOptions = migrate.Options
+
+// MigratedFile is a source file changed by the migration
+type MigratedFile struct {
+	// Name is the file path relative to the migrated root
+	Name   string
+	Path   string
+	Before []byte
+	After  []byte
+}
+
+// Migration contains rewritten files and legacy API usages which are left for manual migration
+type Migration struct {
+	Files []MigratedFile
+	Notes []string
+}
+
+// PlanMigration finds legacy CRUD functions, validators and retries of SDK v2 resources at the path
+// and prepares rewrites, nothing is written
+func PlanMigration(path string, options MigrationOptions) (*Migration, error) {
+	root, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	gens, err := loadProjectGenerators(root)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := migrate.Plan(gens, options)
+	if err != nil {
+		return nil, err
+	}
+	result := &Migration{}
+	for _, note := range plan.Notes {
+		result.Notes = append(result.Notes, note.String())
+	}
+	for _, filename := range plan.Filenames() {
+		before, after, err := plan.Apply(filename)
+		if err != nil {
+			return nil, err
+		}
+		name, err := filepath.Rel(root, filename)
+		if err != nil {
+			name = filename
+		}
+		result.Files = append(result.Files, MigratedFile{
+			Name:   filepath.ToSlash(name),
+			Path:   filename,
+			Before: before,
+			After:  after,
+		})
+	}
+	return result, nil
+}
+
+// Diff returns unified diff of all migrated files
+func (m *Migration) Diff() (string, error) {
+	result := ""
+	for _, file := range m.Files {
+		diff, err := migrate.Diff(file.Name, file.Before, file.After)
+		if err != nil {
+			return "", fmt.Errorf("error building diff of %s: %w", file.Name, err)
+		}
+		result += diff
+	}
+	return result, nil
+}
+
+// Write writes migrated sources to the files
+func (m *Migration) Write() error {
+	for _, file := range m.Files {
+		info, err := os.Stat(file.Path)
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(file.Path, file.After, info.Mode()); err != nil {
+			return fmt.Errorf("error writing %s: %w", file.Path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package lint
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/changelog"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/lineage"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/snapshot"
+)
+
+// SchemaSnapshot contains schemas of all provider resources
+type SchemaSnapshot = snapshot.Provider
+
+// SchemaChange is a single change between two schema snapshots
+type SchemaChange = snapshot.Change
+
+// ChangelogFormats are supported release notes formats
+var ChangelogFormats = changelog.Formats
+
+// LoadSchemaSnapshot extracts schemas from the source tree or reads exported JSON file
+func LoadSchemaSnapshot(path string) (*SchemaSnapshot, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() && strings.HasSuffix(path, ".json") {
+		return snapshot.ReadFile(path)
+	}
+	gens, err := loadProjectGenerators(path)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.FromGenerators(gens), nil
+}
+
+// LoadRevisionSchema extracts schemas from the sources of the local git revision,
+// path is the root directory inside the repository
+func LoadRevisionSchema(path, rev string) (*SchemaSnapshot, error) {
+	return revisionSnapshot(path, rev)
+}
+
+// DiffSchemas returns changes between two schema snapshots, classified as breaking, state-migrating or additive
+func DiffSchemas(prev, next *SchemaSnapshot) []SchemaChange {
+	return snapshot.Diff(prev, next)
+}
+
+// WriteSchemaDiff writes report of the schema changes,
+// error is returned if there are breaking changes
+func WriteSchemaDiff(w io.Writer, changes []SchemaChange) error {
+	if err := snapshot.WriteReport(w, changes); err != nil {
+		return err
+	}
+	if snapshot.HasBreaking(changes) {
+		return fmt.Errorf("breaking schema changes found")
+	}
+	return nil
+}
+
+// WriteChangelog writes release notes of the schema changes in `markdown` or `release-note` format
+func WriteChangelog(w io.Writer, changes []SchemaChange, format string) error {
+	return changelog.Write(w, changes, changelog.Format(format))
+}
+
+// LineageReport contains response struct fields feeding attributes of all resources
+type LineageReport = lineage.Report
+
+// LineageFormats are supported lineage report formats
+var LineageFormats = lineage.Formats
+
+// LoadLineage extracts lineage of the resources and data sources
+func LoadLineage(path string) (*LineageReport, error) {
+	gens, err := loadProjectGenerators(path)
+	if err != nil {
+		return nil, err
+	}
+	return lineage.FromGenerators(gens), nil
+}
+
+// WriteLineage writes lineage report in `text` or `json` format
+func WriteLineage(w io.Writer, report *LineageReport, format string) error {
+	return report.Write(w, lineage.Format(format))
+}
@@ -6,32 +6,112 @@ import (
 	"log"
 
 	"github.com/hashicorp/go-multierror"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/acctest"
 	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/core"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/docs"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/framework"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/generators"
 	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/parser"
+	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint/internal/upgrade"
 	"golang.org/x/tools/go/packages"
 )
 
+// Config describes linter settings
+type Config = core.Config
+
+// Rules lists all known rules
+var Rules = core.Rules
+
+// SchemaImport is a schema package of the given SDK generation, e.g. a vendored SDK fork
+type SchemaImport = core.SchemaImport
+
 // Validate searches for all resource and validate their setters
 func Validate(path string) error {
+	return ValidateWithConfig(path, core.DefaultConfig())
+}
+
+// ValidateWithConfig searches for all resource and validate them using enabled rules
+func ValidateWithConfig(path string, config *Config) error {
+	if err := config.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	log.Println("Start validating packages at", path)
+	gens, project, err := loadGenerators(path, config)
+	if err != nil {
+		return err
+	}
+
+	var mErr *multierror.Error
+	for _, gen := range gens {
+		mErr = multierror.Append(mErr, gen.Validate())
+	}
+	mErr = multierror.Append(mErr, generators.ValidateProject(gens, config))
+	if config.Enabled(core.RuleFrameworkModels) || config.Enabled(core.RuleFrameworkPaths) {
+		mErr = multierror.Append(mErr, framework.Validate(project.Framework, config))
+	}
+	if config.Enabled(core.RuleStateUpgrade) {
+		mErr = multierror.Append(mErr, validateStateUpgrades(path, gens, config))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// validateStateUpgrades compares resource schemas with the baseline, the rule is skipped if there is no baseline
+func validateStateUpgrades(path string, gens []*generators.Generator, config *Config) error {
+	if config.Baseline == "" {
+		log.Printf("No baseline configured, skipping %s rule", core.RuleStateUpgrade)
+		return nil
+	}
+	baseline, err := loadBaseline(path, config)
+	if err != nil {
+		return fmt.Errorf("error loading baseline schema: %w", err)
+	}
+	return upgrade.Validate(gens, baseline, config)
+}
+
+// loadProjectGenerators loads generators using the configuration file of the root directory, if there is one
+func loadProjectGenerators(path string) ([]*generators.Generator, error) {
+	config, err := FindConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	gens, _, err := loadGenerators(path, config)
+	return gens, err
+}
+
+// loadGenerators loads all packages at the path and parses resource generators,
+// the project contains provider-wide information collected from the same packages
+func loadGenerators(path string, config *Config) ([]*generators.Generator, *core.Project, error) {
 	fSet := token.NewFileSet()
 	cfg := &packages.Config{
 		Mode: packages.NeedDeps |
 			packages.NeedImports |
 			packages.NeedSyntax,
-		Fset: fSet,
-		Dir:  path,
+		Fset:  fSet,
+		Dir:   path,
+		Tests: true, // acceptance tests are used by some rules
 	}
-	log.Println("Start validating packages at", path)
 	pkgs, err := packages.Load(cfg, "./...")
 	if err != nil {
-		return fmt.Errorf("error loading packages: %w", err)
+		return nil, nil, fmt.Errorf("error loading packages: %w", err)
 	}
 
-	var mErr *multierror.Error
+	project := core.NewProject()
+	project.Packages = pkgs
+	project.Resources = parser.FindResources(pkgs, fSet)
+	project.ImportSteps = acctest.CollectImportSteps(pkgs, fSet)
+	project.AttrChecks = acctest.CollectAttrChecks(pkgs, fSet)
+	project.Docs = docs.Collect(path, project.Resources, fSet)
+	project.Configs = append(docs.CollectExamples(path, fSet), acctest.CollectConfigs(pkgs, fSet)...)
+	project.Framework = framework.Collect(pkgs, fSet)
+
+	var gens []*generators.Generator
 	pkgCache := map[string]*core.Scope{}
 	for _, pkg := range pkgs {
-		p := parser.NewParser(pkg, fSet, pkgCache) // we need this state to use types and imports later
-		mErr = multierror.Append(mErr, p.Validate())
+		if parser.IsTestVariant(pkg) {
+			continue // generators are validated in the original package
+		}
+		p := parser.NewParser(pkg, fSet, config, project, pkgCache) // we need this state to use types and imports later
+		gens = append(gens, p.Generators()...)
 	}
-	return mErr.ErrorOrNil()
+	return gens, project, nil
 }
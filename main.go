@@ -5,15 +5,30 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/opentelekomcloud-infra/terraform-setter-lint/lint"
 )
 
 const help = "Simple lint checking that all resource attribute setters have " +
 	"corresponding attributes in the resource schema.\n\n" +
-	"\u001B[1mUsage:\u001B[0m\n  terraform-setter-lint \u001B[2m[path]\u001B[0m\n\n" +
+	"\u001B[1mUsage:\u001B[0m\n  terraform-setter-lint \u001B[2m[flags] [path]\u001B[0m\n" +
+	"  terraform-setter-lint schema \u001B[2m<command> [arguments]\u001B[0m\n" +
+	"  terraform-setter-lint changelog \u001B[2m[flags] <old-rev> <new-rev> [path]\u001B[0m\n" +
+	"  terraform-setter-lint docs generate \u001B[2m[flags] [path]\u001B[0m\n" +
+	"  terraform-setter-lint lineage \u001B[2m[flags] [path]\u001B[0m\n" +
+	"  terraform-setter-lint migrate \u001B[2m[flags] [path]\u001B[0m\n\n" +
 	"\u001B[1mArguments:\u001B[0m\n" +
-	"  path - Path to root directory, current dir if not provided.\n"
+	"  path - Path to root directory, current dir if not provided.\n\n" +
+	"\u001B[1mFlags:\u001B[0m\n"
+
+var (
+	configPath = flag.String("config", "", "Path to configuration `file`, "+lint.ConfigFileName+" in the root directory is used by default")
+	enable     = flag.String("enable", "", "Comma-separated list of rules to enable in addition to default ones, or \"all\"")
+	disable    = flag.String("disable", "", "Comma-separated list of rules to disable")
+	listRules  = flag.Bool("rules", false, "List available rules and exit")
+	baseline   = flag.String("baseline", "", "Schema snapshot `file` or git:<revision> used by the state-upgrade rule")
+)
 
 func init() {
 	flag.Usage = func() {
@@ -22,8 +37,56 @@ func init() {
 	}
 }
 
+func splitList(src string) []string {
+	if src == "" {
+		return nil
+	}
+	return strings.Split(src, ",")
+}
+
+func printRules() {
+	for _, r := range lint.Rules {
+		mark := " "
+		if r.Default {
+			mark = "*"
+		}
+		fmt.Printf("%s %-26s %s\n", mark, r.ID, r.Description)
+	}
+	fmt.Println("\nRules marked with `*` are enabled by default.")
+}
+
+func loadConfig(root string) (*lint.Config, error) {
+	if *configPath != "" {
+		return lint.LoadConfig(*configPath)
+	}
+	return lint.FindConfig(root)
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "schema":
+			runSchema(os.Args[2:])
+			return
+		case "changelog":
+			runChangelog(os.Args[2:])
+			return
+		case "docs":
+			runDocs(os.Args[2:])
+			return
+		case "lineage":
+			runLineage(os.Args[2:])
+			return
+		case "migrate":
+			runMigrate(os.Args[2:])
+			return
+		}
+	}
 	flag.Parse()
+	if *listRules {
+		printRules()
+		return
+	}
 	path := "."
 	if flag.NArg() > 0 {
 		path = flag.Arg(0)
@@ -38,9 +101,19 @@ func main() {
 		_, _ = fmt.Fprintln(os.Stderr, err)
 		os.Exit(2)
 	}
+	config, err := loadConfig(path)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	config.Enable = append(config.Enable, splitList(*enable)...)
+	config.Disable = append(config.Disable, splitList(*disable)...)
+	if *baseline != "" {
+		config.Baseline = *baseline
+	}
 	println("Validating resources at", path)
 
-	if err := lint.Validate(path); err != nil {
+	if err := lint.ValidateWithConfig(path, config); err != nil {
 		_, _ = fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
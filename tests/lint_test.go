@@ -7,6 +7,7 @@ import (
 	"log"
 	"math/rand"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -113,6 +114,412 @@ func TestValidateNegativeBadTypes(t *testing.T) {
 	assert.Len(t, me.Errors, 3)
 }
 
+func TestValidateNegativeBadReferences(t *testing.T) {
+	err := lint.Validate(fixturePath("bad_references"))
+	require.Error(t, err)
+	t.Log(err)
+	me := err.(*multierror.Error)
+	assert.Len(t, me.Errors, 8)
+}
+
+func TestValidateNegativeBadSchema(t *testing.T) {
+	assert.NoError(t, lint.Validate(fixturePath("bad_schema")), "schema rules are not enabled by default")
+
+	err := lint.ValidateWithConfig(fixturePath("bad_schema"), &lint.Config{Enable: []string{"all"}})
+	require.Error(t, err)
+	t.Log(err)
+	me := err.(*multierror.Error)
+	assert.Len(t, me.Errors, 9)
+}
+
+func TestValidateUnknownRule(t *testing.T) {
+	err := lint.ValidateWithConfig(fixturePath("good"), &lint.Config{Enable: []string{"schema-unknown"}})
+	assert.Error(t, err)
+}
+
+func TestValidateNegativeBadValueTypes(t *testing.T) {
+	config, err := lint.FindConfig(fixturePath("bad_value_types"))
+	require.NoError(t, err)
+	require.Contains(t, config.Validators, "example.com/m/bad_value_types.validateName")
+
+	err = lint.ValidateWithConfig(fixturePath("bad_value_types"), config)
+	require.Error(t, err)
+	t.Log(err)
+	me := err.(*multierror.Error)
+	assert.Len(t, me.Errors, 5)
+}
+
+func TestValidateNegativeBadRead(t *testing.T) {
+	config, err := lint.FindConfig(fixturePath("bad_read"))
+	require.NoError(t, err)
+
+	err = lint.ValidateWithConfig(fixturePath("bad_read"), config)
+	require.Error(t, err)
+	t.Log(err)
+	me := err.(*multierror.Error)
+	assert.Len(t, me.Errors, 3)
+}
+
+func TestValidateNegativeBadImport(t *testing.T) {
+	config, err := lint.FindConfig(fixturePath("bad_import"))
+	require.NoError(t, err)
+
+	err = lint.ValidateWithConfig(fixturePath("bad_import"), config)
+	require.Error(t, err)
+	t.Log(err)
+	me := err.(*multierror.Error)
+	assert.Len(t, me.Errors, 3)
+}
+
+func TestValidateNegativeBadTestAttributes(t *testing.T) {
+	config, err := lint.FindConfig(fixturePath("bad_test_attributes"))
+	require.NoError(t, err)
+
+	err = lint.ValidateWithConfig(fixturePath("bad_test_attributes"), config)
+	require.Error(t, err)
+	t.Log(err)
+	me := err.(*multierror.Error)
+	assert.Len(t, me.Errors, 8)
+}
+
+func TestValidateNegativeBadUpdate(t *testing.T) {
+	config, err := lint.FindConfig(fixturePath("bad_update"))
+	require.NoError(t, err)
+
+	err = lint.ValidateWithConfig(fixturePath("bad_update"), config)
+	require.Error(t, err)
+	t.Log(err)
+	me := err.(*multierror.Error)
+	assert.Len(t, me.Errors, 3)
+}
+
+func TestValidateNegativeBadSensitive(t *testing.T) {
+	config, err := lint.FindConfig(fixturePath("bad_sensitive"))
+	require.NoError(t, err)
+
+	err = lint.ValidateWithConfig(fixturePath("bad_sensitive"), config)
+	require.Error(t, err)
+	t.Log(err)
+	me := err.(*multierror.Error)
+	assert.Len(t, me.Errors, 3)
+}
+
+func TestValidateNegativeBadConsistency(t *testing.T) {
+	config, err := lint.FindConfig(fixturePath("bad_consistency"))
+	require.NoError(t, err)
+	require.Contains(t, config.Canonical, "tags")
+
+	err = lint.ValidateWithConfig(fixturePath("bad_consistency"), config)
+	require.Error(t, err)
+	t.Log(err)
+	me := err.(*multierror.Error)
+	assert.Len(t, me.Errors, 3)
+}
+
+func TestValidateNegativeBadParity(t *testing.T) {
+	config, err := lint.FindConfig(fixturePath("bad_parity"))
+	require.NoError(t, err)
+
+	err = lint.ValidateWithConfig(fixturePath("bad_parity"), config)
+	require.Error(t, err)
+	t.Log(err)
+	me := err.(*multierror.Error)
+	assert.Len(t, me.Errors, 4)
+}
+
+func TestValidateNegativeBadStateUpgrade(t *testing.T) {
+	config, err := lint.FindConfig(fixturePath("bad_state_upgrade"))
+	require.NoError(t, err)
+	require.Equal(t, "baseline.json", config.Baseline)
+
+	err = lint.ValidateWithConfig(fixturePath("bad_state_upgrade"), config)
+	require.Error(t, err)
+	t.Log(err)
+	me := err.(*multierror.Error)
+	assert.Len(t, me.Errors, 4)
+
+	config.IntentionalChanges = nil
+	err = lint.ValidateWithConfig(fixturePath("bad_state_upgrade"), config)
+	require.Error(t, err)
+	me = err.(*multierror.Error)
+	assert.Len(t, me.Errors, 6, "removed `cidr` is taken for renamed to `description`")
+}
+
+func TestValidateNegativeBadDocs(t *testing.T) {
+	config, err := lint.FindConfig(fixturePath("bad_docs"))
+	require.NoError(t, err)
+
+	err = lint.ValidateWithConfig(fixturePath("bad_docs"), config)
+	require.Error(t, err)
+	t.Log(err)
+	me := err.(*multierror.Error)
+	assert.Len(t, me.Errors, 8)
+}
+
+func TestValidateNegativeBadConfigs(t *testing.T) {
+	config, err := lint.FindConfig(fixturePath("bad_configs"))
+	require.NoError(t, err)
+
+	err = lint.ValidateWithConfig(fixturePath("bad_configs"), config)
+	require.Error(t, err)
+	t.Log(err)
+	me := err.(*multierror.Error)
+	assert.Len(t, me.Errors, 9)
+}
+
+func TestValidateNegativeBadRequestTypes(t *testing.T) {
+	config, err := lint.FindConfig(fixturePath("bad_request_types"))
+	require.NoError(t, err)
+
+	err = lint.ValidateWithConfig(fixturePath("bad_request_types"), config)
+	require.Error(t, err)
+	t.Log(err)
+	me := err.(*multierror.Error)
+	assert.Len(t, me.Errors, 4)
+}
+
+func TestValidateNegativeBadHelpers(t *testing.T) {
+	config, err := lint.FindConfig(fixturePath("bad_helpers"))
+	require.NoError(t, err)
+
+	err = lint.ValidateWithConfig(fixturePath("bad_helpers"), config)
+	require.Error(t, err)
+	t.Log(err)
+	me := err.(*multierror.Error)
+	assert.Len(t, me.Errors, 5)
+}
+
+func TestValidateNegativeBadSetValues(t *testing.T) {
+	config, err := lint.FindConfig(fixturePath("bad_set_values"))
+	require.NoError(t, err)
+
+	err = lint.ValidateWithConfig(fixturePath("bad_set_values"), config)
+	require.Error(t, err)
+	t.Log(err)
+	me := err.(*multierror.Error)
+	assert.Len(t, me.Errors, 4)
+}
+
+func TestValidateNegativeBadFramework(t *testing.T) {
+	config, err := lint.FindConfig(fixturePath("bad_framework"))
+	require.NoError(t, err)
+
+	err = lint.ValidateWithConfig(fixturePath("bad_framework"), config)
+	require.Error(t, err)
+	t.Log(err)
+	me := err.(*multierror.Error)
+	assert.Len(t, me.Errors, 5)
+}
+
+func TestValidateNegativeBadDeleted(t *testing.T) {
+	config, err := lint.FindConfig(fixturePath("bad_deleted"))
+	require.NoError(t, err)
+
+	err = lint.ValidateWithConfig(fixturePath("bad_deleted"), config)
+	require.Error(t, err)
+	t.Log(err)
+	me := err.(*multierror.Error)
+	assert.Len(t, me.Errors, 2)
+}
+
+func TestValidateNegativeBadImplicitGetters(t *testing.T) {
+	config, err := lint.FindConfig(fixturePath("bad_implicit_getters"))
+	require.NoError(t, err)
+
+	err = lint.ValidateWithConfig(fixturePath("bad_implicit_getters"), config)
+	require.Error(t, err)
+	t.Log(err)
+	me := err.(*multierror.Error)
+	assert.Len(t, me.Errors, 3)
+}
+
+func TestValidateSDKGenerations(t *testing.T) {
+	config, err := lint.FindConfig(fixturePath("sdk_generations"))
+	require.NoError(t, err)
+
+	err = lint.ValidateWithConfig(fixturePath("sdk_generations"), config)
+	require.Error(t, err)
+	t.Log(err)
+	me := err.(*multierror.Error)
+	assert.Len(t, me.Errors, 5)
+
+	report, err := lint.LoadLineage(fixturePath("sdk_generations"))
+	require.NoError(t, err)
+	generations := map[string]string{}
+	for _, res := range report.Resources {
+		generations[res.TypeName] = res.SDK
+	}
+	assert.Equal(t, map[string]string{
+		"opentelekomcloud_vpc_v1":    "v1",
+		"opentelekomcloud_subnet_v1": "v2",
+	}, generations)
+
+	config.SchemaImports = append(config.SchemaImports, lint.SchemaImport{
+		Path:       "github.com/example/terraform-plugin-sdk/helper/schema",
+		Generation: "v3",
+	})
+	assert.Error(t, lint.ValidateWithConfig(fixturePath("sdk_generations"), config))
+}
+
+func TestMigrate(t *testing.T) {
+	root := fixturePath("migrate")
+	migration, err := lint.PlanMigration(root, lint.MigrationOptions{})
+	require.NoError(t, err)
+
+	diff, err := migration.Diff()
+	require.NoError(t, err)
+	t.Log(diff)
+	for _, line := range []string{
+		"+\t\tCreateContext: resourceVpcV1Create,",
+		"+\t\t\tStateContext: schema.ImportStatePassthroughContext,",
+		"+\t\t\t\tValidateDiagFunc: validation.ToDiagFunc(validation.StringLenBetween(1, 64)),",
+		"+func resourceVpcV1Create(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {",
+		"+\t\treturn diag.Errorf(\"error creating VPC: %s\", err)",
+		"+\treturn resourceVpcV1Read(ctx, d, meta)",
+		"+\t\treturn diag.FromErr(err)",
+		"+\treturn diag.FromErr(resource.RetryContext(ctx, d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {",
+		"+\t\"github.com/hashicorp/terraform-plugin-sdk/v2/diag\"",
+		"+func dataSourceSubnetV1Read(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {",
+	} {
+		assert.Contains(t, diff, line)
+	}
+	assert.NotContains(t, diff, "+func resourceSubnetV1Read(ctx")
+
+	notes := strings.Join(migration.Notes, "\n")
+	t.Log(notes)
+	assert.Len(t, migration.Notes, 4)
+	assert.Contains(t, notes, "`ResourceSubnetV1` CRUD functions are not migrated: `resourceSubnetV1Read` is also used at")
+	assert.Contains(t, notes, "`resource.Retry` is used without context")
+	assert.Contains(t, notes, "`d.Partial` is deprecated")
+
+	migration, err = lint.PlanMigration(root, lint.MigrationOptions{Errorf: "example.com/m/common/fmterr.Errorf"})
+	require.NoError(t, err)
+	diff, err = migration.Diff()
+	require.NoError(t, err)
+	assert.Contains(t, diff, "+\t\treturn fmterr.Errorf(\"error creating VPC: %w\", err)")
+	assert.Contains(t, diff, "+\t\"example.com/m/common/fmterr\"")
+
+	migration, err = lint.PlanMigration(root, lint.MigrationOptions{})
+	require.NoError(t, err)
+	require.NoError(t, migration.Write())
+	build := exec.Command("go", "build", "./migrate")
+	build.Dir = tmpDir
+	out, err := build.CombinedOutput()
+	require.NoError(t, err, "migrated sources must compile: %s", out)
+
+	migration, err = lint.PlanMigration(root, lint.MigrationOptions{})
+	require.NoError(t, err)
+	for _, file := range migration.Files {
+		assert.NotEqual(t, "vpc.go", file.Name, "migrated resources must not be rewritten twice")
+	}
+}
+
+func TestDocsGenerate(t *testing.T) {
+	root := fixturePath("docs_generate")
+	templates := filepath.Join(root, "templates")
+
+	stale, err := lint.GenerateDocs(root, templates, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docs/data-sources/vpc_subnet_v1.md", "docs/resources/vpc_v1.md"}, stale)
+
+	_, err = lint.GenerateDocs(root, templates, false)
+	require.NoError(t, err)
+	stale, err = lint.GenerateDocs(root, templates, true)
+	require.NoError(t, err)
+	assert.Empty(t, stale)
+
+	resource, err := os.ReadFile(filepath.Join(root, "docs", "resources", "vpc_v1.md"))
+	require.NoError(t, err)
+	t.Log(string(resource))
+	assert.Contains(t, string(resource), "Manages a VPC resource within OpenTelekomCloud.\n")
+	assert.Contains(t, string(resource), "* `description` - (Optional, String, ForceNew) The description of the VPC. "+
+		"Defaults to `\"default\"`. Changing this creates a new resource.\n")
+	assert.Contains(t, string(resource), "The `rule` block supports:\n\n* `port` - (Optional, Number)\n")
+
+	dataSource, err := os.ReadFile(filepath.Join(root, "docs", "data-sources", "vpc_subnet_v1.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(dataSource), "# Data Source: opentelekomcloud_vpc_subnet_v1\n")
+
+	assert.NoError(t, lint.ValidateWithConfig(root, &lint.Config{Enable: []string{"docs-drift"}}))
+}
+
+func TestLineage(t *testing.T) {
+	report, err := lint.LoadLineage(fixturePath("lineage"))
+	require.NoError(t, err)
+	require.Len(t, report.Resources, 1)
+
+	res := report.Resources[0]
+	assert.Equal(t, "opentelekomcloud_compute_instance_v2", res.TypeName)
+	fields := map[string][]string{}
+	for key, sources := range res.Attributes {
+		fields[key] = []string{}
+		for _, src := range sources {
+			fields[key] = append(fields[key], src.String())
+		}
+	}
+	assert.Equal(t, map[string][]string{
+		"name":              {"servers.Server.Name"},
+		"key_pair":          {"servers.Server.KeyName"},
+		"status":            {"servers.Server.Status"},
+		"availability_zone": {"availabilityzones.ServerAvailabilityZoneExt.AvailabilityZone"},
+		"region":            {},
+	}, fields)
+	assert.Contains(t, res.Unexposed["servers.Server"], "AccessIPv4")
+	assert.NotContains(t, res.Unexposed["servers.Server"], "Name")
+
+	text := &strings.Builder{}
+	require.NoError(t, lint.WriteLineage(text, report, "text"))
+	t.Log(text)
+	assert.Contains(t, text.String(), "  name <- servers.Server.Name (instance.go:71)\n")
+}
+
+func TestSchemaDiff(t *testing.T) {
+	prev, err := lint.LoadSchemaSnapshot(fixturePath("schema_diff/old"))
+	require.NoError(t, err)
+	next, err := lint.LoadSchemaSnapshot(fixturePath("schema_diff/new"))
+	require.NoError(t, err)
+
+	kinds := map[string]int{}
+	for _, change := range lint.DiffSchemas(prev, next) {
+		t.Log(change)
+		kinds[string(change.Kind)]++
+	}
+	assert.Equal(t, map[string]int{"breaking": 7, "state-migrating": 2, "additive": 4}, kinds)
+}
+
+func TestSchemaExport(t *testing.T) {
+	prev, err := lint.LoadSchemaSnapshot(fixturePath("schema_diff/old"))
+	require.NoError(t, err)
+
+	path := filepath.Join(tmpDir, "schema.json")
+	require.NoError(t, prev.WriteFile(path))
+	exported, err := lint.LoadSchemaSnapshot(path)
+	require.NoError(t, err)
+	assert.Empty(t, lint.DiffSchemas(prev, exported))
+}
+
+func TestChangelog(t *testing.T) {
+	prev, err := lint.LoadSchemaSnapshot(fixturePath("schema_diff/old"))
+	require.NoError(t, err)
+	next, err := lint.LoadSchemaSnapshot(fixturePath("schema_diff/new"))
+	require.NoError(t, err)
+	changes := lint.DiffSchemas(prev, next)
+
+	markdown := &strings.Builder{}
+	require.NoError(t, lint.WriteChangelog(markdown, changes, "markdown"))
+	t.Log(markdown)
+	assert.Contains(t, markdown.String(), "### New resources\n\n- `opentelekomcloud_compute_servergroup_v2`\n")
+	assert.Contains(t, markdown.String(), "### Removed attributes\n")
+	for _, helper := range []string{"secgroupRuleSchema", "servergroupPolicySchema"} {
+		assert.NotContains(t, markdown.String(), helper, "`Elem` helpers are not resources")
+	}
+
+	notes := &strings.Builder{}
+	require.NoError(t, lint.WriteChangelog(notes, changes, "release-note"))
+	assert.Equal(t, 11, strings.Count(notes.String(), "```release-note:"))
+	assert.Contains(t, notes.String(), "```release-note:new-resource\nopentelekomcloud_compute_servergroup_v2\n```")
+}
+
 func TestValidateAcceptance(t *testing.T) {
 	err := lint.Validate(fixturePath("complicated"))
 	require.NoError(t, err)